@@ -27,6 +27,22 @@ func BenchmarkTree_ResolveStringsInternalTest(b *testing.B) {
 	}, b)
 }
 
+func BenchmarkTree_ResolveStringsInternalTestWorkers(b *testing.B) {
+	benchmarkTreeResolveStrings(&Tree{
+		ResolveInternal: true,
+		ResolveTest:     true,
+		Workers:         8,
+	}, b)
+}
+
+func BenchmarkTree_ResolveStringsInternalTestCached(b *testing.B) {
+	benchmarkTreeResolveStrings(&Tree{
+		ResolveInternal: true,
+		ResolveTest:     true,
+		Importer:        &CachedImporter{Dir: b.TempDir()},
+	}, b)
+}
+
 func benchmarkTreeResolveStrings(t *Tree, b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		if err := t.Resolve("strings"); err != nil {
@@ -0,0 +1,289 @@
+package depth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// cacheVersion is included in each cache key, and must be changed whenever the format
+// of the cache entries changes.
+const cacheVersion = "1"
+
+// CachedImporter is an Importer that stores the packages imported by another Importer
+// on disk, so that repeated resolutions, such as in CI or editor integrations, skip
+// packages that haven't changed.
+//
+// Each package is first located using the build.FindOnly mode, and is then cached by
+// its import path, a hash of the contents of its directory and the build context used
+// to import it. Packages are invalidated by any change to the files of their directory,
+// and the packages in GOROOT by a change of GOROOT or Go version. Failed imports are
+// never cached.
+//
+// Locating and hashing a package reads every file in its directory, so importing a
+// package that isn't cached is slower than importing it directly. The cache only pays
+// off when most packages are unchanged between resolutions.
+//
+// CachedImporter is safe for concurrent use, provided its Importer is.
+type CachedImporter struct {
+	// Importer is used to import packages that aren't cached. If nil, build.Default
+	// is used.
+	Importer Importer
+
+	// Dir is the directory the cache is stored in. If empty, a depth directory
+	// within the user's cache directory is used.
+	Dir string
+
+	mu      sync.Mutex
+	modules map[string]*Module
+}
+
+// cacheEntry is a package stored by a CachedImporter.
+type cacheEntry struct {
+	Package *build.Package `json:"package"`
+	Module  *Module        `json:"module,omitempty"`
+}
+
+// DefaultCacheDir returns the default directory of a CachedImporter.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "depth"), nil
+}
+
+// Import implements the Importer interface, returning the cached package if its
+// directory hasn't changed since it was cached.
+func (c *CachedImporter) Import(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+	i := c.importer()
+	if im&build.FindOnly != 0 {
+		return i.Import(name, srcDir, im)
+	}
+
+	loc, err := i.Import(name, srcDir, build.FindOnly)
+	if err != nil || loc.Dir == "" {
+		return i.Import(name, srcDir, im)
+	}
+
+	key, err := c.key(loc, im)
+	if err != nil {
+		return i.Import(name, srcDir, im)
+	}
+
+	if e, ok := c.read(key); ok {
+		c.setModule(e.Package.ImportPath, e.Module)
+		return e.Package, nil
+	}
+
+	pkg, err := i.Import(name, srcDir, im)
+	if err != nil {
+		return pkg, err
+	}
+
+	e := cacheEntry{Package: pkg}
+	if m, ok := i.(ModuleImporter); ok {
+		e.Module = m.Module(pkg.ImportPath)
+		c.setModule(pkg.ImportPath, e.Module)
+	}
+
+	// The cache is only an optimization, so failing to write it isn't an error.
+	c.write(key, &e)
+	return pkg, nil
+}
+
+// Module implements the ModuleImporter interface, returning the Module of a package
+// previously imported from the cache or the Importer.
+func (c *CachedImporter) Module(importPath string) *Module {
+	c.mu.Lock()
+	m, ok := c.modules[importPath]
+	c.mu.Unlock()
+	if ok {
+		return m
+	}
+
+	if m, ok := c.importer().(ModuleImporter); ok {
+		return m.Module(importPath)
+	}
+	return nil
+}
+
+// Clear removes every package from the cache.
+func (c *CachedImporter) Clear() error {
+	dir, err := c.dir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// importer returns the Importer of packages that aren't cached.
+func (c *CachedImporter) importer() Importer {
+	if c.Importer == nil {
+		return &build.Default
+	}
+	return c.Importer
+}
+
+// dir returns the directory the cache is stored in.
+func (c *CachedImporter) dir() (string, error) {
+	if c.Dir != "" {
+		return c.Dir, nil
+	}
+	return DefaultCacheDir()
+}
+
+// setModule records the Module of a package for the Module method.
+func (c *CachedImporter) setModule(importPath string, m *Module) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.modules == nil {
+		c.modules = make(map[string]*Module)
+	}
+	c.modules[importPath] = m
+}
+
+// key returns the cache key of a located package, which changes along with the build
+// context, the import mode or the contents of the package directory.
+func (c *CachedImporter) key(loc *build.Package, im build.ImportMode) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "depth %v\n%v\n%v\n%v\n%v\n", cacheVersion, c.context(), loc.ImportPath, loc.Dir, im)
+
+	// The stdlib only changes along with GOROOT and the Go version, which are part of
+	// the context, so its directories aren't hashed.
+	if !loc.Goroot {
+		if err := hashDir(h, loc.Dir); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// context returns a description of the build context of the Importer, including
+// everything that affects which files are part of a package.
+func (c *CachedImporter) context() string {
+	ctx := &build.Default
+	var env []string
+	switch i := c.importer().(type) {
+	case *build.Context:
+		ctx = i
+	case *GoListImporter:
+		if i.Context != nil {
+			ctx = i.Context
+		}
+		env = i.Env
+	}
+
+	return strings.Join([]string{
+		fmt.Sprintf("%T", c.importer()),
+		ctx.GOOS,
+		ctx.GOARCH,
+		fmt.Sprint(ctx.CgoEnabled),
+		strings.Join(ctx.BuildTags, ","),
+		strings.Join(ctx.ToolTags, ","),
+		strings.Join(ctx.ReleaseTags, ","),
+		ctx.GOROOT,
+		ctx.GOPATH,
+		ctx.Compiler,
+		ctx.InstallSuffix,
+		strings.Join(env, ","),
+		os.Getenv("GOFLAGS"),
+		os.Getenv("GO111MODULE"),
+	}, "\n")
+}
+
+// hashDir writes the name and contents of each file in the directory to the Writer.
+// Subdirectories are other packages, so they aren't included.
+func hashDir(w io.Writer, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%v\n", e.Name())
+		_, err = io.Copy(w, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the file a cache entry is stored in.
+func (c *CachedImporter) path(key string) (string, error) {
+	dir, err := c.dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, key[:2], key+".json"), nil
+}
+
+// read returns the cache entry with the key provided, if any.
+func (c *CachedImporter) read(key string) (*cacheEntry, bool) {
+	path, err := c.path(key)
+	if err != nil {
+		return nil, false
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var e cacheEntry
+	if err := json.NewDecoder(f).Decode(&e); err != nil || e.Package == nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// write stores the cache entry with the key provided. The entry is written to a
+// temporary file first, so concurrent readers never see a partial entry.
+func (c *CachedImporter) write(key string, e *cacheEntry) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := json.NewEncoder(f).Encode(e); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,149 @@
+package depth
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// countingImporter is an Importer that counts the packages fully imported by its
+// Context, or build.Default.
+type countingImporter struct {
+	ctx     *build.Context
+	imports int
+}
+
+func (c *countingImporter) Import(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+	if im&build.FindOnly == 0 {
+		c.imports++
+	}
+	if c.ctx != nil {
+		return c.ctx.Import(name, srcDir, im)
+	}
+	return build.Default.Import(name, srcDir, im)
+}
+
+func TestCachedImporter_Import(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(t.TempDir(), "pkg")
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile := func(name, contents string) {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("pkg.go", "package pkg\n\nimport \"strings\"\n\nvar _ = strings.ToLower\n")
+
+	tests := []struct {
+		change  func()
+		imports int
+		deps    []string
+	}{
+		{nil, 1, []string{"strings"}},
+		{nil, 0, []string{"strings"}},
+		{func() { writeFile("pkg.go", "package pkg\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n") }, 1, []string{"fmt"}},
+		{func() { writeFile("other.go", "package pkg\n\nimport \"strings\"\n\nvar _ = strings.ToLower\n") }, 1, []string{"fmt", "strings"}},
+		{nil, 0, []string{"fmt", "strings"}},
+	}
+
+	for idx, tt := range tests {
+		if tt.change != nil {
+			tt.change()
+		}
+
+		// A new CachedImporter is used each time, as with separate runs.
+		var counter countingImporter
+		c := CachedImporter{Importer: &counter, Dir: dir}
+		pkg, err := c.Import(".", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if counter.imports != tt.imports {
+			t.Fatalf("[%v] Unexpected imports, expected=%v, got=%v", idx, tt.imports, counter.imports)
+		} else if !reflect.DeepEqual(pkg.Imports, tt.deps) {
+			t.Fatalf("[%v] Unexpected Imports, expected=%v, got=%v", idx, tt.deps, pkg.Imports)
+		}
+	}
+
+	var counter countingImporter
+	c := CachedImporter{Importer: &counter, Dir: dir}
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Import(".", src, 0); err != nil {
+		t.Fatal(err)
+	} else if counter.imports != 1 {
+		t.Fatalf("Unexpected imports after Clear, expected=%v, got=%v", 1, counter.imports)
+	}
+}
+
+func TestCachedImporter_ImportErrors(t *testing.T) {
+	var counter countingImporter
+	c := CachedImporter{Importer: &counter, Dir: t.TempDir()}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Import("github.com/KyleBanks/notreal", ".", 0); err == nil {
+			t.Fatal("Expected error for unknown package")
+		}
+	}
+
+	// FindOnly imports are passed through.
+	if pkg, err := c.Import("strings", ".", build.FindOnly); err != nil {
+		t.Fatal(err)
+	} else if pkg.Name != "" {
+		t.Fatalf("Unexpected Name for FindOnly, expected none, got=%v", pkg.Name)
+	}
+
+	if counter.imports != 2 {
+		t.Fatalf("Unexpected imports, expected=%v, got=%v", 2, counter.imports)
+	}
+}
+
+func TestCachedImporter_context(t *testing.T) {
+	ctx := build.Default
+	ctx.GOOS = "windows"
+	tagged := build.Default
+	tagged.BuildTags = []string{"custom"}
+
+	importers := []Importer{
+		nil,
+		&ctx,
+		&tagged,
+		&GoListImporter{},
+		&GoListImporter{Context: &ctx},
+	}
+
+	seen := make(map[string]int)
+	for idx, i := range importers {
+		c := CachedImporter{Importer: i}
+		key := c.context()
+		if prev, ok := seen[key]; ok {
+			t.Fatalf("[%v] Unexpected context, same as importer %v", idx, prev)
+		}
+		seen[key] = idx
+	}
+}
+
+func TestTree_ResolveCached(t *testing.T) {
+	dir := t.TempDir()
+
+	var trees []Tree
+	for i := 0; i < 2; i++ {
+		tr := Tree{ResolveTest: true, Importer: &CachedImporter{Dir: dir}}
+		if err := tr.Resolve("github.com/KyleBanks/depth"); err != nil {
+			t.Fatal(err)
+		}
+		trees = append(trees, tr)
+	}
+
+	if a, b := pkgNames(*trees[0].Root), pkgNames(*trees[1].Root); !reflect.DeepEqual(a, b) {
+		t.Fatalf("Unexpected cached Tree, expected=%v, got=%v", a, b)
+	} else if a, b := trees[0].Root.Deps[0].Files, trees[1].Root.Deps[0].Files; !reflect.DeepEqual(a, b) {
+		t.Fatalf("Unexpected cached Files, expected=%v, got=%v", a, b)
+	}
+}
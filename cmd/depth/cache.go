@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/KyleBanks/depth"
+)
+
+// handleCleanCache removes every package from the on-disk cache used by the Tree,
+// or the default cache if the Tree doesn't use one.
+func handleCleanCache(t *depth.Tree, args []string) error {
+	c, ok := t.Importer.(*depth.CachedImporter)
+	if !ok {
+		c = &depth.CachedImporter{}
+	}
+
+	if err := c.Clear(); err != nil {
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+	fmt.Println("Cache cleaned.")
+	return nil
+}
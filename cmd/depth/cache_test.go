@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/KyleBanks/depth"
+)
+
+func Test_parseCache(t *testing.T) {
+	dir := t.TempDir()
+
+	tr, _ := parse([]string{"-cache-dir", dir})
+	if c, ok := tr.Importer.(*depth.CachedImporter); !ok {
+		t.Fatalf("Unexpected Importer, expected=*depth.CachedImporter, got=%T", tr.Importer)
+	} else if c.Dir != dir {
+		t.Fatalf("Unexpected Dir, expected=%v, got=%v", dir, c.Dir)
+	} else if c.Importer != tr.Context {
+		t.Fatalf("Unexpected cached Importer, expected=%v, got=%v", tr.Context, c.Importer)
+	}
+
+	tr, _ = parse([]string{"-cache"})
+	if c, ok := tr.Importer.(*depth.CachedImporter); !ok {
+		t.Fatalf("Unexpected Importer, expected=*depth.CachedImporter, got=%T", tr.Importer)
+	} else if c.Dir != "" {
+		t.Fatalf("Unexpected Dir, expected=default, got=%v", c.Dir)
+	}
+
+	tr, _ = parse([]string{"-cache", "-no-cache"})
+	if tr.Importer != nil {
+		t.Fatalf("Unexpected Importer with -no-cache, expected=nil, got=%T", tr.Importer)
+	}
+
+	tr, _ = parse(nil)
+	if tr.Importer != nil {
+		t.Fatalf("Unexpected Importer, expected=nil, got=%T", tr.Importer)
+	}
+}
+
+func Example_handleCleanCache() {
+	dir, err := os.MkdirTemp("", "depth")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	t, _ := parse([]string{"-cache-dir", dir})
+	if err := t.Resolve("strings"); err != nil {
+		panic(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	fmt.Println(len(entries) > 0)
+
+	handleCleanCache(t, nil)
+	_, err = os.Stat(dir)
+	fmt.Println(os.IsNotExist(err))
+	// Output:
+	// true
+	// Cache cleaned.
+	// true
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+// errViolations is returned when the dependencies of a package break the rules of
+// a Policy.
+var errViolations = errors.New("dependency policy violated")
+
+// handleCheck checks the packages provided against the Policy in the rules file of
+// the command-line options.
+func handleCheck(t *depth.Tree, args []string) error {
+	return checkPkgs(t, args, opts)
+}
+
+// checkPkgs resolves the packages provided, which default to "./...", together and
+// checks their dependencies against the Policy in the rules file of the options. Each
+// violation is written to Stdout, and an error is returned if there are any.
+func checkPkgs(t *depth.Tree, args []string, o options) error {
+	p, err := readPolicy(o.rules)
+	if err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", o.rules, err)
+		return err
+	}
+
+	if len(args) == 0 {
+		args = []string{"./..."}
+	}
+	if err := t.ResolveAll(args...); err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", strings.Join(args, " "), err)
+		return err
+	}
+
+	violations, err := p.Check(t)
+	if err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", o.rules, err)
+		return err
+	}
+
+	writeViolations(os.Stdout, t.Graph, violations)
+	fmt.Printf("%d violations.\n", len(violations))
+	if len(violations) > 0 {
+		return errViolations
+	}
+	return nil
+}
+
+// readPolicy reads the Policy from the file provided.
+func readPolicy(name string) (*depth.Policy, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return depth.ReadPolicy(f)
+}
+
+// writeViolations writes each Violation, along with the chain of imports that leads
+// to it and the positions of each import.
+func writeViolations(w io.Writer, g *depth.Graph, violations []depth.Violation) {
+	for _, v := range violations {
+		fmt.Fprintf(w, "[%v] %v\n", v.Rule.Name, v.Message)
+		fmt.Fprintf(w, "%v%v\n", outputClosedPadding, strings.Join(explainFiles(g, v.Path), " -> "))
+	}
+}
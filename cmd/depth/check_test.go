@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/KyleBanks/depth"
+)
+
+func Example_handleCheck() {
+	var t depth.Tree
+
+	checkPkgs(&t, []string{"./testdata/rdeps/a"}, options{rules: "testdata/check/rules.json"})
+	// Output:
+	// [a-must-not-import-c] github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a must not import github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	//   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b (a.go:5) -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c (b.go:5)
+	// [b-must-not-import-c] github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b must not import github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	//   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b (a.go:5) -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c (b.go:5)
+	// [no-external] github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b must not import external package github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	//   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b (a.go:5) -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c (b.go:5)
+	// [shallow] github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c is 2 imports deep, the maximum is 1
+	//   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b (a.go:5) -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c (b.go:5)
+	// 4 violations.
+}
+
+func Example_handleCheckPass() {
+	var t depth.Tree
+
+	checkPkgs(&t, []string{"./testdata/rdeps/..."}, options{rules: "testdata/check/pass.json"})
+	// Output:
+	// 0 violations.
+}
+
+func Example_handleCheckInvalidRules() {
+	var t depth.Tree
+
+	checkPkgs(&t, []string{"./testdata/rdeps/..."}, options{rules: "testdata/check/missing.json"})
+	// Output:
+	// 'testdata/check/missing.json': FATAL: open testdata/check/missing.json: no such file or directory
+}
+
+func Example_handleCheckLayers() {
+	var t depth.Tree
+
+	checkPkgs(&t, []string{"./testdata/layers/handlers"}, options{rules: "testdata/check/layers.json"})
+	// Output:
+	// [layers] github.com/KyleBanks/depth/cmd/depth/testdata/layers/handlers must not import github.com/KyleBanks/depth/cmd/depth/testdata/layers/repositories, skipping layer ./testdata/layers/services/...
+	//   github.com/KyleBanks/depth/cmd/depth/testdata/layers/handlers -> github.com/KyleBanks/depth/cmd/depth/testdata/layers/repositories (handlers.go:5)
+	// [layers] github.com/KyleBanks/depth/cmd/depth/testdata/layers/models must not import github.com/KyleBanks/depth/cmd/depth/testdata/layers/services/format from the higher layer ./testdata/layers/services/...
+	//   github.com/KyleBanks/depth/cmd/depth/testdata/layers/handlers -> github.com/KyleBanks/depth/cmd/depth/testdata/layers/repositories (handlers.go:5) -> github.com/KyleBanks/depth/cmd/depth/testdata/layers/models (repositories.go:3) -> github.com/KyleBanks/depth/cmd/depth/testdata/layers/services/format (models.go:4)
+	// 2 violations.
+}
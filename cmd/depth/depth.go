@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"go/build"
 	"io"
 	"os"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/KyleBanks/depth"
 )
@@ -18,22 +22,108 @@ const (
 	outputPrefixLast    = "└ "
 )
 
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatDOT  = "dot"
+	formatCSV  = "csv"
+)
+
+// narrowSymbols is the number of symbols that an import can use, at most, to be
+// reported by -symbols as a candidate for vendoring or removal.
+const narrowSymbols = 2
+
+const (
+	explainAll      = "all"
+	explainShortest = "shortest"
+)
+
+const (
+	sortName   = "name"
+	sortWeight = "weight"
+)
+
 var outputJSON bool
-var explainPkg string
+var opts options
+
+// options contains the configuration used to output each resolved Tree.
+type options struct {
+	format  string
+	explain explainer
+	cycles  bool
+	rules   string
+	files   bool
+	symbols bool
+	sort    string
+	tui     bool
+	addr    string
+	vulnDB  string
+
+	// watch re-resolves the packages whenever their source files change, polling
+	// them every watchInterval.
+	watch         bool
+	watchInterval time.Duration
 
-type summary struct {
-	numInternal int
-	numExternal int
-	numTesting  int
+	// platforms contains the platforms to resolve and merge the Tree for, if any.
+	platforms []depth.Platform
+
+	// group collapses the packages of the Graph into modules, repositories or
+	// domains, if set.
+	group depth.Grouping
+}
+
+// explainer contains the configuration used to explain how a target package is imported.
+type explainer struct {
+	target string
+	mode   string
+	max    int
+}
+
+// commands contains the handler of each subcommand, which are provided a Tree
+// configured by the command-line flags and the remaining arguments.
+var commands = map[string]func(t *depth.Tree, args []string) error{
+	"rdeps":       handleRdeps,
+	"diff":        handleDiff,
+	"check":       handleCheck,
+	"serve":       handleServe,
+	"clean-cache": handleCleanCache,
+	"vuln":        handleVuln,
+	"license":     handleLicense,
+	"tidy-report": handleTidyReport,
+	"metrics":     handleMetrics,
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			t, args := parse(os.Args[2:])
+			if err := checkCommandOptions(os.Args[1], opts); err != nil {
+				os.Exit(1)
+			}
+			if err := cmd(t, args); err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	t, pkgs := parse(os.Args[1:])
-	if err := handlePkgs(t, pkgs, outputJSON, explainPkg); err != nil {
+	if err := handlePkgs(t, pkgs, opts); err != nil {
 		os.Exit(1)
 	}
 }
 
+// checkCommandOptions returns an error if the options include flags that only apply
+// to the dependency tree, and are not supported by the subcommand provided.
+func checkCommandOptions(name string, o options) error {
+	if o.group != "" {
+		err := fmt.Errorf("the %v command does not support -group", name)
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+	return nil
+}
+
 // parse constructs a depth.Tree from command-line arguments, and returns the
 // remaining user-supplied package names
 func parse(args []string) (*depth.Tree, []string) {
@@ -43,69 +133,285 @@ func parse(args []string) (*depth.Tree, []string) {
 	f.BoolVar(&t.ResolveInternal, "internal", false, "If set, resolves dependencies of internal (stdlib) packages.")
 	f.BoolVar(&t.ResolveTest, "test", false, "If set, resolves dependencies used for testing.")
 	f.IntVar(&t.MaxDepth, "max", 0, "Sets the maximum depth of dependencies to resolve.")
-	f.BoolVar(&outputJSON, "json", false, "If set, outputs the depencies in JSON format.")
-	f.StringVar(&explainPkg, "explain", "", "If set, show which packages import the specified target")
+	f.IntVar(&t.Workers, "workers", runtime.NumCPU(), "Sets the number of packages to import concurrently.")
+	f.BoolVar(&outputJSON, "json", false, "If set, outputs the depencies in JSON format. Shorthand for -format=json.")
+	f.StringVar(&opts.format, "format", formatText, "Sets the output format, either 'text', 'json' or 'dot' (Graphviz), or 'csv' for the license and metrics commands.")
+	f.StringVar(&opts.explain.target, "explain", "", "If set, show which packages import the specified target")
+	f.StringVar(&opts.explain.mode, "explain-mode", explainShortest, "Sets the paths shown by -explain, either only the 'shortest' path or 'all' simple paths, which can be slow on large graphs without -explain-max.")
+	f.IntVar(&opts.explain.max, "explain-max", 0, "Sets the maximum number of paths shown by -explain.")
+	f.BoolVar(&opts.cycles, "cycles", false, "If set, shows the import cycles found, including those created by test imports when used with -test.")
+	f.StringVar((*string)(&opts.group), "group", "", "If set, collapses packages into groups, either by 'module', 'repo' (repository) or 'domain'. Supports the text, json and dot formats, -explain and -platforms, but not subcommands or other flags.")
+	f.StringVar(&opts.sort, "sort", sortName, "Sets the order of dependencies, either by 'name' or by transitive 'weight', which also shows the weight of each package, or for the metrics command by 'ca', 'ce', 'instability', 'fan-in', 'fan-out' or 'depth'.")
+	f.BoolVar(&opts.tui, "tui", false, "If set, explores the dependencies interactively, reading commands from Stdin.")
+	f.BoolVar(&opts.files, "files", false, "If set, shows the source files and lines containing each import.")
+	f.BoolVar(&opts.symbols, "symbols", false, "If set, type-checks each package to show the exported symbols used from each import, and the imports using only one or two.")
+	f.BoolVar(&opts.watch, "watch", false, "If set, watches the source files of the resolved packages and shows the dependencies added or removed whenever they change.")
+	f.DurationVar(&opts.watchInterval, "watch-interval", depth.DefaultWatchInterval, "Sets how often -watch checks for changes.")
+	f.StringVar(&opts.addr, "addr", "localhost:8080", "Sets the address the serve command listens on.")
+	f.StringVar(&opts.vulnDB, "db", "", "Sets the directory of the OSV vulnerability database used by the vuln command.")
+	f.StringVar(&opts.rules, "rules", "depth.json", "Sets the JSON file containing the dependency rules used by the check command.")
+	ctx := build.Default
+	f.StringVar(&ctx.GOOS, "goos", build.Default.GOOS, "Sets the target operating system used to resolve packages.")
+	f.StringVar(&ctx.GOARCH, "goarch", build.Default.GOARCH, "Sets the target architecture used to resolve packages.")
+	f.BoolVar(&ctx.CgoEnabled, "cgo", build.Default.CgoEnabled, "If set, resolves packages with cgo enabled. Defaults to the host's setting, or false when -goos or -goarch differ from the host.")
+	tags := f.String("tags", "", "Sets a comma-separated list of additional build tags used to resolve packages.")
+	platforms := f.String("platforms", "", "Sets a comma-separated list of goos/goarch platforms to resolve and merge, such as 'linux/amd64,windows/amd64'.")
+	importer := f.String("importer", "build", "Sets the importer used to resolve packages, either 'build' (go/build) or 'golist' (module-aware go list).")
+	cache := f.Bool("cache", false, "If set, caches the packages imported by go/build on disk, which speeds up repeated runs but slows down the first.")
+	noCache := f.Bool("no-cache", false, "If set, doesn't use the on-disk package cache, even with -cache or -cache-dir.")
+	cacheDir := f.String("cache-dir", "", "Sets the directory of the on-disk package cache, defaulting to a depth directory within the user's cache directory. Implies -cache.")
 	f.Parse(args)
 
+	ctx.BuildTags = splitList(*tags)
+	t.KeepCgo = isFlagSet(f, "cgo")
+	if !t.KeepCgo && (ctx.GOOS != build.Default.GOOS || ctx.GOARCH != build.Default.GOARCH) {
+		// As with the go command, cgo is disabled by default when cross-compiling.
+		ctx.CgoEnabled = false
+	}
+	t.ResolveSymbols = opts.symbols
+	t.Context = &ctx
+
+	switch *importer {
+	case "build":
+		// The Tree defaults to go/build, using its Context. The go list importer
+		// already locates packages by listing them, so only go/build is cached.
+		if (*cache || *cacheDir != "") && !*noCache {
+			t.Importer = &depth.CachedImporter{Importer: &ctx, Dir: *cacheDir}
+		}
+	case "golist":
+		t.Importer = &depth.GoListImporter{Context: &ctx}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown importer '%v', expected 'build' or 'golist'\n", *importer)
+		os.Exit(2)
+	}
+
+	opts.platforms = nil
+	for _, name := range splitList(*platforms) {
+		p, err := depth.ParsePlatform(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		opts.platforms = append(opts.platforms, p)
+	}
+
+	if outputJSON {
+		opts.format = formatJSON
+	}
+
 	return &t, f.Args()
 }
 
+// isFlagSet returns true if the named flag was set on the command-line.
+func isFlagSet(f *flag.FlagSet, name string) bool {
+	set := false
+	f.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// useModules configures the Tree to resolve packages with the go list importer, which
+// is the only one to report the module providing each package.
+func useModules(t *depth.Tree) {
+	if _, ok := t.Importer.(*depth.GoListImporter); !ok {
+		t.Importer = &depth.GoListImporter{Context: t.Context}
+	}
+}
+
+// splitList splits a comma-separated list, ignoring empty values.
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 // handlePkgs takes a slice of package names, resolves a Tree on them,
 // and outputs each Tree to Stdout.
-func handlePkgs(t *depth.Tree, pkgs []string, outputJSON bool, explainPkg string) error {
-	for _, pkg := range pkgs {
+func handlePkgs(t *depth.Tree, pkgs []string, o options) error {
+	switch o.format {
+	case "", formatText, formatJSON, formatDOT:
+	default:
+		err := fmt.Errorf("unknown format '%v', expected 'text', 'json' or 'dot'", o.format)
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
 
-		err := t.Resolve(pkg)
-		if err != nil {
-			fmt.Printf("'%v': FATAL: %v\n", pkg, err)
+	switch o.explain.mode {
+	case "", explainAll, explainShortest:
+	default:
+		err := fmt.Errorf("unknown explain mode '%v', expected 'all' or 'shortest'", o.explain.mode)
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+
+	switch o.sort {
+	case "", sortName, sortWeight:
+	default:
+		err := fmt.Errorf("unknown sort '%v', expected 'name' or 'weight'", o.sort)
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+
+	switch o.group {
+	case "", depth.GroupModule, depth.GroupRepo, depth.GroupDomain:
+	default:
+		err := fmt.Errorf("unknown group '%v', expected 'module', 'repo' or 'domain'", o.group)
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+
+	if o.group != "" && (o.cycles || o.tui || o.watch || o.files || o.symbols || o.sort == sortWeight) {
+		err := fmt.Errorf("-group does not support -cycles, -tui, -watch, -files, -symbols or -sort=weight")
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+
+	// Modules are only known to the go list importer.
+	if o.group == depth.GroupModule {
+		useModules(t)
+	}
+
+	if o.watch {
+		if o.format == formatJSON || o.tui || len(o.platforms) > 0 {
+			err := fmt.Errorf("-watch does not support the json format, -tui or -platforms")
+			fmt.Printf("FATAL: %v\n", err)
 			return err
 		}
 
-		if outputJSON {
-			writePkgJSON(os.Stdout, *t.Root)
-			continue
+		return handleWatch(t, pkgs, o)
+	}
+
+	if len(o.platforms) > 0 {
+		if o.format == formatJSON {
+			err := fmt.Errorf("the json format does not support -platforms")
+			fmt.Printf("FATAL: %v\n", err)
+			return err
 		}
 
-		if explainPkg != "" {
-			writeExplain(os.Stdout, *t.Root, []string{}, explainPkg)
-			continue
+		if err := t.ResolvePlatforms(o.platforms, pkgs...); err != nil {
+			fmt.Printf("'%v': FATAL: %v\n", strings.Join(pkgs, " "), err)
+			return err
 		}
 
-		writePkg(os.Stdout, *t.Root)
-		writePkgSummary(os.Stdout, *t.Root)
+		writeTree(os.Stdout, t, o)
+		return nil
 	}
+
+	if len(pkgs) == 0 {
+		return nil
+	}
+
+	// Multiple packages and patterns are resolved together as the roots of a single Tree.
+	if hasPattern(pkgs) || len(pkgs) > 1 {
+		if err := t.ResolveAll(pkgs...); err != nil {
+			fmt.Printf("'%v': FATAL: %v\n", strings.Join(pkgs, " "), err)
+			return err
+		}
+
+		writeTree(os.Stdout, t, o)
+		return nil
+	}
+
+	if err := t.Resolve(pkgs[0]); err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", pkgs[0], err)
+		return err
+	}
+
+	writeTree(os.Stdout, t, o)
 	return nil
 }
 
-// writePkgSummary writes a summary of all packages in a tree
-func writePkgSummary(w io.Writer, pkg depth.Pkg) {
-	var sum summary
-	set := make(map[string]struct{})
-	for _, p := range pkg.Deps {
-		collectSummary(&sum, p, set)
+// hasPattern returns true if any of the package names provided contain the "..."
+// wildcard.
+func hasPattern(pkgs []string) bool {
+	for _, pkg := range pkgs {
+		if strings.Contains(pkg, "...") {
+			return true
+		}
 	}
-	fmt.Fprintf(w, "%d dependencies (%d internal, %d external, %d testing).\n",
-		sum.numInternal+sum.numExternal,
-		sum.numInternal,
-		sum.numExternal,
-		sum.numTesting)
+	return false
 }
 
-func collectSummary(sum *summary, pkg depth.Pkg, nameSet map[string]struct{}) {
-	if _, ok := nameSet[pkg.Name]; !ok {
-		nameSet[pkg.Name] = struct{}{}
-		if pkg.Internal {
-			sum.numInternal++
-		} else {
-			sum.numExternal++
+// writeTree writes the resolved Tree to the Writer in the format of the options.
+func writeTree(w io.Writer, t *depth.Tree, o options) {
+	if o.group != "" {
+		writeGroups(w, t, o)
+		return
+	}
+
+	switch o.format {
+	case formatJSON:
+		for _, r := range t.Roots {
+			writePkgJSON(w, *r)
 		}
-		if pkg.Test {
-			sum.numTesting++
+		return
+	case formatDOT:
+		writeGraphDOT(w, t.Graph)
+		return
+	}
+
+	if o.explain.target != "" {
+		for _, r := range t.Graph.Roots() {
+			writeExplain(w, t.Graph, r.Name, o.explain, o.files)
 		}
-		for _, p := range pkg.Deps {
-			collectSummary(sum, p, nameSet)
+		return
+	}
+
+	if o.cycles {
+		writeCycles(w, t.Cycles())
+		return
+	}
+
+	if o.tui {
+		newExplorer(t.Graph, os.Stdin, w).run()
+		return
+	}
+
+	// Graphs merged across platforms no longer match any single Tree.
+	if len(t.Graph.Platforms()) > 0 {
+		writeGraph(w, t.Graph, o)
+		writePkgSummary(w, t.Graph.Summary())
+		if o.sort == sortWeight {
+			writeWeightSummary(w, t.Graph)
+		}
+		if o.symbols {
+			writeNarrowImports(w, t.Graph)
 		}
+		if err := t.Err(); err != nil {
+			fmt.Fprintln(w, err)
+		}
+		return
 	}
+
+	for _, r := range t.Roots {
+		writePkg(w, t.Graph, *r, o)
+	}
+	writePkgSummary(w, t.Summary())
+	if o.sort == sortWeight {
+		writeWeightSummary(w, t.Graph)
+	}
+	if o.symbols {
+		writeNarrowImports(w, t.Graph)
+	}
+	if err := t.Err(); err != nil {
+		fmt.Fprintln(w, err)
+	}
+}
+
+// writePkgSummary writes a summary of all packages in a tree
+func writePkgSummary(w io.Writer, sum depth.Summary) {
+	fmt.Fprintf(w, "%d dependencies (%d internal, %d external, %d testing).\n",
+		sum.Total(),
+		sum.Internal,
+		sum.External,
+		sum.Testing)
 }
 
 // writePkgJSON writes the full Pkg as JSON to the provided Writer.
@@ -115,16 +421,160 @@ func writePkgJSON(w io.Writer, p depth.Pkg) {
 	e.Encode(p)
 }
 
-func writePkg(w io.Writer, p depth.Pkg) {
-	fmt.Fprintf(w, "%s\n", p.String())
+// writeWeightSummary writes the combined Weight of every package in the Graph that
+// isn't only used for testing.
+func writeWeightSummary(w io.Writer, g *depth.Graph) {
+	var files, lines, symbols int
+	for _, n := range g.Nodes() {
+		if n.Test {
+			continue
+		}
+
+		weight := g.Weight(n.Name)
+		files += weight.Files
+		lines += weight.Lines
+		symbols += weight.Symbols
+	}
+
+	fmt.Fprintf(w, "%d files, %d lines, %d symbols.\n", files, lines, symbols)
+}
+
+func writePkg(w io.Writer, g *depth.Graph, p depth.Pkg, o options) {
+	fmt.Fprintf(w, "%s", p.String())
+	if o.sort == sortWeight {
+		fmt.Fprint(w, weightSuffix(g, p.Name))
+	}
+	fmt.Fprintln(w)
+
+	deps := sortPkgs(g, p.Deps, o.sort)
+	for idx, d := range deps {
+		writePkgRec(w, g, d, []bool{true}, idx == len(deps)-1, o)
+	}
+}
+
+// writePkg recursively prints a Pkg and its dependencies to the Writer provided,
+// optionally followed by the positions of the import and the weight of each Pkg.
+func writePkgRec(w io.Writer, g *depth.Graph, p depth.Pkg, closed []bool, isLast bool, o options) {
+	var prefix string
+
+	for _, c := range closed {
+		if c {
+			prefix += outputClosedPadding
+			continue
+		}
+
+		prefix += outputOpenPadding
+	}
+
+	closed = append(closed, false)
+	if isLast {
+		prefix += outputPrefixLast
+		closed[len(closed)-1] = true
+	} else {
+		prefix += outputPrefix
+	}
+
+	fmt.Fprintf(w, "%v%v", prefix, p.String())
+	if o.files {
+		fmt.Fprint(w, positionsSuffix(p.Files))
+	}
+	if o.symbols {
+		fmt.Fprint(w, symbolsSuffix(p.Symbols))
+	}
+	if o.sort == sortWeight {
+		fmt.Fprint(w, weightSuffix(g, p.Name))
+	}
+	fmt.Fprintln(w)
+
+	deps := sortPkgs(g, p.Deps, o.sort)
+	for idx, d := range deps {
+		writePkgRec(w, g, d, closed, idx == len(deps)-1, o)
+	}
+}
+
+// sortPkgs returns the Pkgs in the order provided. The name order is that of the
+// Tree, and the weight order puts the heaviest packages, including their transitive
+// dependencies, first.
+func sortPkgs(g *depth.Graph, pkgs []depth.Pkg, order string) []depth.Pkg {
+	if order != sortWeight {
+		return pkgs
+	}
+
+	sorted := append([]depth.Pkg{}, pkgs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return g.TransitiveWeight(sorted[i].Name).Lines > g.TransitiveWeight(sorted[j].Name).Lines
+	})
+	return sorted
+}
+
+// weightSuffix formats the Weight of a package and its transitive Weight to follow
+// the name of the package.
+func weightSuffix(g *depth.Graph, name string) string {
+	w, t := g.Weight(name), g.TransitiveWeight(name)
+	return fmt.Sprintf(" (%d files, %d lines, %d symbols; %d lines total)", w.Files, w.Lines, w.Symbols, t.Lines)
+}
+
+// positionsSuffix formats import positions to follow the name of a package, or
+// returns an empty string if there are none.
+func positionsSuffix(pos []depth.Position) string {
+	if len(pos) == 0 {
+		return ""
+	}
+
+	s := make([]string, len(pos))
+	for i, p := range pos {
+		s[i] = p.String()
+	}
+	return " (" + strings.Join(s, ", ") + ")"
+}
 
-	for idx, d := range p.Deps {
-		writePkgRec(w, d, []bool{true}, idx == len(p.Deps)-1)
+// symbolsSuffix formats the symbols used from an import to follow the name of a
+// package, or returns an empty string if there are none.
+func symbolsSuffix(symbols []string) string {
+	if len(symbols) == 0 {
+		return ""
 	}
+	return " [" + strings.Join(symbols, ", ") + "]"
 }
 
-// writePkg recursively prints a Pkg and its dependencies to the Writer provided.
-func writePkgRec(w io.Writer, p depth.Pkg, closed []bool, isLast bool) {
+// writeNarrowImports writes the imports of external packages that use narrowSymbols
+// symbols or fewer, if any.
+func writeNarrowImports(w io.Writer, g *depth.Graph) {
+	edges := g.NarrowImports(narrowSymbols)
+	if len(edges) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%d imports use %d or fewer symbols:\n", len(edges), narrowSymbols)
+	for _, e := range edges {
+		fmt.Fprintf(w, "%v%v -> %v%v\n", outputClosedPadding, e.From, e.To, symbolsSuffix(e.Symbols))
+	}
+}
+
+// writeGraph writes each root of the Graph and its imports in the same form as
+// writePkg. The imports of each package are only shown the first time it is written,
+// and imports that don't apply to every platform of the Graph list their platforms.
+//
+// The Nodes of a grouped Graph are followed by the number of packages they contain.
+func writeGraph(w io.Writer, g *depth.Graph, o options) {
+	seen := make(map[string]struct{})
+	for _, r := range g.Roots() {
+		fmt.Fprintf(w, "%s%s", r.Pkg.String(), groupSuffix(r, nil))
+		if o.sort == sortWeight {
+			fmt.Fprint(w, weightSuffix(g, r.Name))
+		}
+		fmt.Fprintln(w)
+		seen[r.Name] = struct{}{}
+
+		edges := sortEdges(g, g.EdgesFrom(r.Name), o.sort)
+		for idx, e := range edges {
+			writeGraphRec(w, g, e, []bool{true}, idx == len(edges)-1, seen, o)
+		}
+	}
+}
+
+// writeGraphRec recursively writes the package imported by an Edge and its imports.
+func writeGraphRec(w io.Writer, g *depth.Graph, e depth.Edge, closed []bool, isLast bool, seen map[string]struct{}, o options) {
 	var prefix string
 
 	for _, c := range closed {
@@ -144,20 +594,90 @@ func writePkgRec(w io.Writer, p depth.Pkg, closed []bool, isLast bool) {
 		prefix += outputPrefix
 	}
 
-	fmt.Fprintf(w, "%v%v\n", prefix, p.String())
+	n := g.Node(e.To)
+	fmt.Fprintf(w, "%v%v%v", prefix, n.Pkg.String(), groupSuffix(n, &e))
+	if len(e.Platforms) < len(g.Platforms()) {
+		fmt.Fprintf(w, " [%v]", strings.Join(e.Platforms, ", "))
+	}
+	if o.files {
+		fmt.Fprint(w, positionsSuffix(e.Files))
+	}
+	if o.symbols {
+		fmt.Fprint(w, symbolsSuffix(e.Symbols))
+	}
+	if o.sort == sortWeight {
+		fmt.Fprint(w, weightSuffix(g, n.Name))
+	}
+	fmt.Fprintln(w)
+
+	if _, ok := seen[n.Name]; ok {
+		return
+	}
+	seen[n.Name] = struct{}{}
+
+	edges := sortEdges(g, g.EdgesFrom(n.Name), o.sort)
+	for idx, d := range edges {
+		writeGraphRec(w, g, d, closed, idx == len(edges)-1, seen, o)
+	}
+}
+
+// sortEdges returns the Edges in the order provided, as with sortPkgs.
+func sortEdges(g *depth.Graph, edges []depth.Edge, order string) []depth.Edge {
+	if order != sortWeight {
+		return edges
+	}
+
+	sorted := append([]depth.Edge{}, edges...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return g.TransitiveWeight(sorted[i].To).Lines > g.TransitiveWeight(sorted[j].To).Lines
+	})
+	return sorted
+}
 
-	for idx, d := range p.Deps {
-		writePkgRec(w, d, closed, idx == len(p.Deps)-1)
+// writeCycles writes each import cycle as an ordered path.
+func writeCycles(w io.Writer, cycles []depth.Cycle) {
+	for _, c := range cycles {
+		fmt.Fprint(w, strings.Join(c.Path, " -> "))
+		if c.Test {
+			fmt.Fprint(w, " (test)")
+		}
+		fmt.Fprintln(w)
 	}
+
+	fmt.Fprintf(w, "%d import cycles.\n", len(cycles))
 }
 
-// writeExplain shows possible paths for a given package.
-func writeExplain(w io.Writer, pkg depth.Pkg, stack []string, explain string) {
-	stack = append(stack, pkg.Name)
-	if pkg.Name == explain {
-		fmt.Fprintln(w, strings.Join(stack, " -> "))
+// writeExplain shows the paths from the root package to the target of the explainer,
+// optionally including the positions of each import along the way. Only the shortest
+// path is shown unless the mode of the explainer is explainAll.
+func writeExplain(w io.Writer, g *depth.Graph, root string, e explainer, files bool) {
+	var paths [][]string
+	if e.mode != explainAll {
+		if p := g.ShortestPath(root, e.target); p != nil {
+			paths = append(paths, p)
+		}
+	} else {
+		paths = g.AllPaths(root, e.target, e.max)
 	}
-	for _, p := range pkg.Deps {
-		writeExplain(w, p, stack, explain)
+
+	for _, p := range paths {
+		if files {
+			p = explainFiles(g, p)
+		}
+		fmt.Fprintln(w, strings.Join(p, " -> "))
+	}
+}
+
+// explainFiles returns a copy of the path with the positions of each import following
+// the imported package.
+func explainFiles(g *depth.Graph, path []string) []string {
+	out := append([]string{}, path...)
+	for i := 1; i < len(path); i++ {
+		for _, e := range g.EdgesFrom(path[i-1]) {
+			if e.To == path[i] {
+				out[i] += positionsSuffix(e.Files)
+			}
+		}
 	}
+	return out
 }
@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"go/build"
+	"reflect"
+	"runtime"
 	"testing"
 
 	"github.com/KyleBanks/depth"
@@ -40,16 +43,166 @@ func Test_parse(t *testing.T) {
 			t.Fatalf("[%v] Unexpected MaxDepth, expected=%v, got=%v", idx, tt.depth, tr.MaxDepth)
 		} else if outputJSON != tt.json {
 			t.Fatalf("[%v] Unexpected outputJSON, expected=%v, got=%v", idx, tt.json, outputJSON)
-		} else if explainPkg != tt.explain {
-			t.Fatalf("[%v] Unexpected explainPkg, expected=%v, got=%v", idx, tt.explain, explainPkg)
+		} else if tt.json && opts.format != formatJSON {
+			t.Fatalf("[%v] Unexpected format, expected=%v, got=%v", idx, formatJSON, opts.format)
+		} else if opts.explain.target != tt.explain {
+			t.Fatalf("[%v] Unexpected explain target, expected=%v, got=%v", idx, tt.explain, opts.explain.target)
 		}
 	}
 }
 
+func Test_parseFormat(t *testing.T) {
+	parse([]string{"-format=dot"})
+	if opts.format != formatDOT {
+		t.Fatalf("Unexpected format, expected=%v, got=%v", formatDOT, opts.format)
+	}
+}
+
+func Test_parseExplain(t *testing.T) {
+	parse([]string{"-explain=strings", "-explain-mode=shortest", "-explain-max=3"})
+	expected := explainer{target: "strings", mode: explainShortest, max: 3}
+	if opts.explain != expected {
+		t.Fatalf("Unexpected explainer, expected=%+v, got=%+v", expected, opts.explain)
+	}
+
+	parse([]string{"-explain=strings"})
+	if opts.explain.mode != explainShortest {
+		t.Fatalf("Unexpected default explain mode, expected=%v, got=%v", explainShortest, opts.explain.mode)
+	}
+}
+
+func Test_parseCycles(t *testing.T) {
+	parse([]string{"-cycles"})
+	if !opts.cycles {
+		t.Fatal("Expected cycles to be set")
+	}
+}
+
+func Test_parseSort(t *testing.T) {
+	parse([]string{})
+	if opts.sort != sortName {
+		t.Fatalf("Unexpected default sort, expected=%v, got=%v", sortName, opts.sort)
+	}
+
+	parse([]string{"-sort=weight"})
+	if opts.sort != sortWeight {
+		t.Fatalf("Unexpected sort, expected=%v, got=%v", sortWeight, opts.sort)
+	}
+}
+
+func Test_parseTui(t *testing.T) {
+	parse([]string{"-tui"})
+	if !opts.tui {
+		t.Fatal("Expected tui to be set")
+	}
+}
+
+func Test_parseFiles(t *testing.T) {
+	parse([]string{"-files"})
+	if !opts.files {
+		t.Fatal("Expected files to be set")
+	}
+}
+
+func Test_parseGroup(t *testing.T) {
+	parse([]string{"-group=module"})
+	if opts.group != depth.GroupModule {
+		t.Fatalf("Unexpected group, expected=%v, got=%v", depth.GroupModule, opts.group)
+	}
+}
+
+func Test_parseRules(t *testing.T) {
+	parse([]string{})
+	if opts.rules != "depth.json" {
+		t.Fatalf("Unexpected default rules, expected=%v, got=%v", "depth.json", opts.rules)
+	}
+
+	parse([]string{"-rules=rules.json"})
+	if opts.rules != "rules.json" {
+		t.Fatalf("Unexpected rules, expected=%v, got=%v", "rules.json", opts.rules)
+	}
+}
+
+func Test_parseWorkers(t *testing.T) {
+	tr, _ := parse([]string{})
+	if tr.Workers != runtime.NumCPU() {
+		t.Fatalf("Unexpected default Workers, expected=%v, got=%v", runtime.NumCPU(), tr.Workers)
+	}
+
+	tr, _ = parse([]string{"-workers=1"})
+	if tr.Workers != 1 {
+		t.Fatalf("Unexpected Workers, expected=%v, got=%v", 1, tr.Workers)
+	}
+}
+
+func Test_parseContext(t *testing.T) {
+	tr, _ := parse([]string{"-goos=windows", "-goarch=arm64", "-cgo=false", "-tags=foo, bar", "-platforms=linux/amd64,darwin/arm64"})
+	if tr.Context == nil {
+		t.Fatal("Expected Context to be set")
+	} else if tr.Context.GOOS != "windows" || tr.Context.GOARCH != "arm64" || tr.Context.CgoEnabled {
+		t.Fatalf("Unexpected Context, expected=windows/arm64 without cgo, got=%v/%v cgo=%v", tr.Context.GOOS, tr.Context.GOARCH, tr.Context.CgoEnabled)
+	} else if !reflect.DeepEqual(tr.Context.BuildTags, []string{"foo", "bar"}) {
+		t.Fatalf("Unexpected BuildTags, expected=%v, got=%v", []string{"foo", "bar"}, tr.Context.BuildTags)
+	}
+
+	expected := []depth.Platform{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "darwin", GOARCH: "arm64"}}
+	if !reflect.DeepEqual(opts.platforms, expected) {
+		t.Fatalf("Unexpected platforms, expected=%v, got=%v", expected, opts.platforms)
+	}
+
+	tr, _ = parse([]string{"-goos=" + otherGOOS()})
+	if tr.Context.CgoEnabled {
+		t.Fatalf("Unexpected CgoEnabled for %v, expected=false", tr.Context.GOOS)
+	}
+	tr, _ = parse([]string{"-goos=" + otherGOOS(), "-cgo"})
+	if !tr.Context.CgoEnabled {
+		t.Fatalf("Unexpected CgoEnabled with -cgo, expected=true")
+	}
+	tr, _ = parse(nil)
+	if tr.Context.CgoEnabled != build.Default.CgoEnabled {
+		t.Fatalf("Unexpected CgoEnabled for the host, expected=%v, got=%v", build.Default.CgoEnabled, tr.Context.CgoEnabled)
+	}
+
+	tr, _ = parse([]string{"-importer=golist", "-tags=foo"})
+	if g := tr.Importer.(*depth.GoListImporter); g.Context != tr.Context {
+		t.Fatalf("Unexpected GoListImporter Context, expected=%v, got=%v", tr.Context, g.Context)
+	} else if opts.platforms != nil {
+		t.Fatalf("Unexpected platforms, expected=nil, got=%v", opts.platforms)
+	}
+}
+
+// otherGOOS returns an operating system other than the host's.
+func otherGOOS() string {
+	if build.Default.GOOS == "windows" {
+		return "linux"
+	}
+	return "windows"
+}
+
+func Example_checkCommandOptions() {
+	checkCommandOptions("metrics", options{group: depth.GroupRepo})
+	fmt.Println(checkCommandOptions("metrics", options{}))
+	// Output:
+	// FATAL: the metrics command does not support -group
+	// <nil>
+}
+
+func Test_parseImporter(t *testing.T) {
+	tr, _ := parse([]string{"-importer=build"})
+	if tr.Importer != nil {
+		t.Fatalf("Unexpected Importer, expected=nil (go/build), got=%T", tr.Importer)
+	}
+
+	tr, _ = parse([]string{"-importer=golist"})
+	if _, ok := tr.Importer.(*depth.GoListImporter); !ok {
+		t.Fatalf("Unexpected Importer, expected=*depth.GoListImporter, got=%T", tr.Importer)
+	}
+}
+
 func Example_handlePkgsStrings() {
 	var t depth.Tree
 
-	handlePkgs(&t, []string{"strings"}, false, "")
+	handlePkgs(&t, []string{"strings"}, options{})
 	// Output:
 	// strings
 	//   ├ errors
@@ -66,7 +219,7 @@ func Example_handlePkgsTestStrings() {
 	var t depth.Tree
 	t.ResolveTest = true
 
-	handlePkgs(&t, []string{"strings"}, false, "")
+	handlePkgs(&t, []string{"strings"}, options{})
 	// Output:
 	// strings
 	//   ├ bytes
@@ -89,37 +242,176 @@ func Example_handlePkgsTestStrings() {
 func Example_handlePkgsDepth() {
 	var t depth.Tree
 
-	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth"}, false, "")
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth"}, options{})
 	// Output:
 	// github.com/KyleBanks/depth/cmd/depth
+	//   ├ bufio
+	//   ├ embed
+	//   ├ encoding/csv
 	//   ├ encoding/json
+	//   ├ errors
 	//   ├ flag
 	//   ├ fmt
+	//   ├ go/build
 	//   ├ io
+	//   ├ io/fs
+	//   ├ net/http
 	//   ├ os
+	//   ├ path/filepath
+	//   ├ runtime
+	//   ├ sort
+	//   ├ strconv
 	//   ├ strings
+	//   ├ text/tabwriter
+	//   ├ time
 	//   └ github.com/KyleBanks/depth
+	//     ├ bufio
 	//     ├ bytes
+	//     ├ crypto/sha256
+	//     ├ encoding/hex
+	//     ├ encoding/json
 	//     ├ errors
+	//     ├ fmt
+	//     ├ go/ast
 	//     ├ go/build
+	//     ├ go/parser
+	//     ├ go/scanner
+	//     ├ go/token
+	//     ├ go/types
+	//     ├ io
 	//     ├ os
+	//     ├ os/exec
 	//     ├ path
+	//     ├ path/filepath
+	//     ├ regexp
 	//     ├ sort
-	//     └ strings
-	// 12 dependencies (11 internal, 1 external, 0 testing).
+	//     ├ strconv
+	//     ├ strings
+	//     ├ sync
+	//     ├ time
+	//     └ unicode
+	// 33 dependencies (32 internal, 1 external, 0 testing).
 }
 
 func Example_handlePkgsUnknown() {
 	var t depth.Tree
 
-	handlePkgs(&t, []string{"notreal"}, false, "")
+	handlePkgs(&t, []string{"notreal"}, options{})
 	// Output:
 	// 'notreal': FATAL: unable to resolve root package
 }
 
+func Example_handlePkgsErrors() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/errors"}, options{})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/errors
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/errors/constrained (unresolved: build-constraints)
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/errors/invalid (unresolved: parse-error)
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/notreal (unresolved: missing)
+	// 3 dependencies (0 internal, 3 external, 0 testing).
+	// 3 packages could not be resolved: github.com/KyleBanks/depth/cmd/depth/testdata/errors/constrained (build-constraints), github.com/KyleBanks/depth/cmd/depth/testdata/errors/invalid (parse-error), github.com/KyleBanks/depth/cmd/depth/testdata/notreal (missing)
+}
+
+func Example_handlePkgsNone() {
+	var t depth.Tree
+
+	fmt.Println(handlePkgs(&t, nil, options{}))
+	// Output:
+	// <nil>
+}
+
+func Example_handlePkgsPattern() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"./testdata/rdeps/..."}, options{})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	//     └ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// 0 dependencies (0 internal, 0 external, 0 testing).
+}
+
+func Example_handlePkgsMultiple() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"./testdata/rdeps/b", "./testdata/rdeps/c"}, options{})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// 0 dependencies (0 internal, 0 external, 0 testing).
+}
+
+func Example_handlePkgsPatternUnmatched() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"./testdata/rdeps/notreal..."}, options{})
+	// Output:
+	// './testdata/rdeps/notreal...': FATAL: no packages match the patterns
+}
+
+func Example_handlePkgsPlatforms() {
+	// Cgo is disabled for platforms other than the host, which these rarely are.
+	var t depth.Tree
+	platforms := []depth.Platform{{GOOS: "linux", GOARCH: "386"}, {GOOS: "windows", GOARCH: "amd64"}}
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/platforms"}, options{platforms: platforms})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/platforms
+	//   ├ fmt
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/platforms/unix [linux/386]
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/platforms/windows [windows/amd64]
+	// 3 dependencies (1 internal, 2 external, 0 testing).
+}
+
+func Example_handlePkgsPlatformsJson() {
+	var t depth.Tree
+	platforms := []depth.Platform{{GOOS: "linux", GOARCH: "amd64"}}
+
+	handlePkgs(&t, []string{"strings"}, options{format: formatJSON, platforms: platforms})
+	// Output:
+	// FATAL: the json format does not support -platforms
+}
+
+func Example_handlePkgsSortWeight() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/weight"}, options{sort: sortWeight})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/weight (1 files, 10 lines, 1 symbols; 42 lines total)
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/weight/util (1 files, 27 lines, 3 symbols; 27 lines total)
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/weight/lib (1 files, 5 lines, 1 symbols; 5 lines total)
+	// 2 dependencies (0 internal, 2 external, 0 testing).
+	// 3 files, 42 lines, 5 symbols.
+}
+
+func Example_handlePkgsSortName() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/weight"}, options{sort: sortName})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/weight
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/weight/lib
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/weight/util
+	// 2 dependencies (0 internal, 2 external, 0 testing).
+}
+
+func Example_handlePkgsUnknownSort() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"strings"}, options{sort: "size"})
+	// Output:
+	// FATAL: unknown sort 'size', expected 'name' or 'weight'
+}
+
 func Example_handlePkgsJson() {
 	var t depth.Tree
-	handlePkgs(&t, []string{"strings"}, true, "")
+	handlePkgs(&t, []string{"strings"}, options{format: formatJSON})
 
 	// Output:
 	// {
@@ -131,54 +423,297 @@ func Example_handlePkgsJson() {
 	//       "name": "errors",
 	//       "internal": true,
 	//       "resolved": true,
-	//       "deps": null
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "reader.go",
+	//           "line": 8
+	//         }
+	//       ]
+	//     },
+	//     {
+	//       "name": "internal/abi",
+	//       "internal": true,
+	//       "resolved": true,
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "builder.go",
+	//           "line": 8
+	//         }
+	//       ]
 	//     },
 	//     {
 	//       "name": "internal/bytealg",
 	//       "internal": true,
 	//       "resolved": true,
-	//       "deps": null
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "builder.go",
+	//           "line": 9
+	//         },
+	//         {
+	//           "file": "compare.go",
+	//           "line": 7
+	//         },
+	//         {
+	//           "file": "strings.go",
+	//           "line": 11
+	//         }
+	//       ]
+	//     },
+	//     {
+	//       "name": "internal/stringslite",
+	//       "internal": true,
+	//       "resolved": true,
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "clone.go",
+	//           "line": 8
+	//         },
+	//         {
+	//           "file": "strings.go",
+	//           "line": 12
+	//         }
+	//       ]
 	//     },
 	//     {
 	//       "name": "io",
 	//       "internal": true,
 	//       "resolved": true,
-	//       "deps": null
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "reader.go",
+	//           "line": 9
+	//         },
+	//         {
+	//           "file": "replace.go",
+	//           "line": 8
+	//         }
+	//       ]
+	//     },
+	//     {
+	//       "name": "iter",
+	//       "internal": true,
+	//       "resolved": true,
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "iter.go",
+	//           "line": 8
+	//         }
+	//       ]
+	//     },
+	//     {
+	//       "name": "math/bits",
+	//       "internal": true,
+	//       "resolved": true,
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "strings.go",
+	//           "line": 13
+	//         }
+	//       ]
 	//     },
 	//     {
 	//       "name": "sync",
 	//       "internal": true,
 	//       "resolved": true,
-	//       "deps": null
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "replace.go",
+	//           "line": 9
+	//         }
+	//       ]
 	//     },
 	//     {
 	//       "name": "unicode",
 	//       "internal": true,
 	//       "resolved": true,
-	//       "deps": null
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "iter.go",
+	//           "line": 9
+	//         },
+	//         {
+	//           "file": "strings.go",
+	//           "line": 14
+	//         }
+	//       ]
 	//     },
 	//     {
 	//       "name": "unicode/utf8",
 	//       "internal": true,
 	//       "resolved": true,
-	//       "deps": null
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "builder.go",
+	//           "line": 10
+	//         },
+	//         {
+	//           "file": "iter.go",
+	//           "line": 10
+	//         },
+	//         {
+	//           "file": "reader.go",
+	//           "line": 10
+	//         },
+	//         {
+	//           "file": "strings.go",
+	//           "line": 15
+	//         }
+	//       ]
 	//     },
 	//     {
 	//       "name": "unsafe",
 	//       "internal": true,
 	//       "resolved": true,
-	//       "deps": null
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "builder.go",
+	//           "line": 11
+	//         }
+	//       ]
+	//     }
+	//   ]
+	// }
+}
+
+func Example_handlePkgsJsonFiles() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b"}, options{format: formatJSON})
+	// Output:
+	// {
+	//   "name": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//   "internal": false,
+	//   "resolved": true,
+	//   "deps": [
+	//     {
+	//       "name": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c",
+	//       "internal": false,
+	//       "resolved": true,
+	//       "deps": null,
+	//       "files": [
+	//         {
+	//           "file": "b.go",
+	//           "line": 5
+	//         }
+	//       ]
 	//     }
 	//   ]
 	// }
+}
+
+func Example_handlePkgsUnknownFormat() {
+	var t depth.Tree
 
+	handlePkgs(&t, []string{"strings"}, options{format: "xml"})
+	// Output:
+	// FATAL: unknown format 'xml', expected 'text', 'json' or 'dot'
 }
 
 func Example_handlePkgsExplain() {
 	var t depth.Tree
 
-	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth"}, false, "strings")
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth"}, options{explain: explainer{target: "strings", mode: explainAll}})
 	// Output:
 	// github.com/KyleBanks/depth/cmd/depth -> strings
 	// github.com/KyleBanks/depth/cmd/depth -> github.com/KyleBanks/depth -> strings
 }
+
+func Example_handlePkgsExplainFiles() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a"}, options{explain: explainer{target: "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c"}, files: true})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b (a.go:5) -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c (b.go:5)
+}
+
+func Example_handlePkgsSymbols() {
+	t := depth.Tree{ResolveSymbols: true}
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/symbols/app"}, options{symbols: true})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/symbols/app
+	//   ├ strings [Builder, Builder.String, Builder.WriteString, Join]
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/symbols/narrow [Name]
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/symbols/side
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/symbols/wide [Counter, Counter.Add, Counter.String, Name, Step]
+	//     └ strconv [Itoa]
+	// 5 dependencies (2 internal, 3 external, 0 testing).
+	// 1 imports use 2 or fewer symbols:
+	//   github.com/KyleBanks/depth/cmd/depth/testdata/symbols/app -> github.com/KyleBanks/depth/cmd/depth/testdata/symbols/narrow [Name]
+}
+
+func Example_handlePkgsFiles() {
+	t := depth.Tree{ResolveTest: true}
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/dot"}, options{files: true})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/dot
+	//   ├ strings (dot.go:5)
+	//   ├ testing (dot_test.go:4)
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/dot/lib (dot.go:7)
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/notreal (unresolved: missing) (dot.go:8)
+	// 4 dependencies (2 internal, 2 external, 1 testing).
+	// 1 packages could not be resolved: github.com/KyleBanks/depth/cmd/depth/testdata/notreal (missing)
+}
+
+func Example_handlePkgsExplainShortest() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth"}, options{explain: explainer{target: "strings", mode: explainShortest}})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth -> strings
+}
+
+func Example_handlePkgsExplainMax() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth"}, options{explain: explainer{target: "strings", mode: explainAll, max: 1}})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth -> strings
+}
+
+func Example_handlePkgsExplainUnknownMode() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"strings"}, options{explain: explainer{target: "strings", mode: "longest"}})
+	// Output:
+	// FATAL: unknown explain mode 'longest', expected 'all' or 'shortest'
+}
+
+func Example_handlePkgsCycles() {
+	t := depth.Tree{ResolveTest: true}
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/cycles/a"}, options{cycles: true})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/cycles/a -> github.com/KyleBanks/depth/cmd/depth/testdata/cycles/b -> github.com/KyleBanks/depth/cmd/depth/testdata/cycles/a (test)
+	// 1 import cycles.
+}
+
+func Example_handlePkgsNoCycles() {
+	var t depth.Tree
+
+	handlePkgs(&t, []string{"github.com/KyleBanks/depth/cmd/depth/testdata/cycles/a"}, options{cycles: true})
+	// Output:
+	// 0 import cycles.
+}
+
+// vendorTree returns a Tree resolving the vendored module in the testdata directory
+// provided, which provides module versions without a network connection.
+func vendorTree(dir string) *depth.Tree {
+	return &depth.Tree{
+		Dir:      dir,
+		Importer: &depth.GoListImporter{Env: []string{"GOFLAGS=-mod=vendor"}},
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+// errDiffUsage is returned when the diff command isn't provided two Trees to compare.
+var errDiffUsage = errors.New("usage: depth diff [flags] <old> <new>")
+
+// handleDiff compares the dependencies of two packages, each of which is either a
+// JSON file written by `depth -json` or a source directory, and writes the changes
+// to Stdout.
+func handleDiff(t *depth.Tree, args []string) error {
+	if len(args) != 2 {
+		fmt.Println(errDiffUsage)
+		return errDiffUsage
+	}
+
+	var trees []*depth.Tree
+	for _, name := range args {
+		tr, err := loadTree(t, name)
+		if err != nil {
+			fmt.Printf("'%v': FATAL: %v\n", name, err)
+			return err
+		}
+		trees = append(trees, tr)
+	}
+
+	writeDiff(os.Stdout, trees[0], trees[1], depth.Diff(trees[0], trees[1]))
+	return nil
+}
+
+// loadTree reads a Tree from a JSON file, or resolves the package in a source
+// directory using the configuration of the Tree provided.
+func loadTree(cfg *depth.Tree, name string) (*depth.Tree, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return depth.ReadTree(f)
+	}
+
+	t := depth.Tree{
+		ResolveInternal: cfg.ResolveInternal,
+		ResolveTest:     cfg.ResolveTest,
+		MaxDepth:        cfg.MaxDepth,
+		Workers:         cfg.Workers,
+		Importer:        cfg.Importer,
+		Context:         cfg.Context,
+		Dir:             name,
+	}
+
+	// The GoListImporter caches packages by import path, which differ between
+	// revisions of the same module.
+	if g, ok := cfg.Importer.(*depth.GoListImporter); ok {
+		t.Importer = &depth.GoListImporter{Env: g.Env, Context: g.Context}
+	}
+
+	pkgs, err := t.Expand(".")
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Resolve(pkgs[0]); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// writeDiff writes the added, removed and moved dependencies between two Trees, and
+// the change in their summaries.
+func writeDiff(w io.Writer, a, b *depth.Tree, d *depth.TreeDiff) {
+	for _, name := range d.Added {
+		fmt.Fprintf(w, "+ %v (%v)\n", name, strings.Join(b.Graph.RootPath(name), " -> "))
+	}
+	for _, name := range d.Removed {
+		fmt.Fprintf(w, "- %v (%v)\n", name, strings.Join(a.Graph.RootPath(name), " -> "))
+	}
+	for _, m := range d.Moved {
+		fmt.Fprintf(w, "~ %v (%v => %v)\n", m.Name, strings.Join(m.Before, " -> "), strings.Join(m.After, " -> "))
+	}
+
+	fmt.Fprintf(w, "%v dependencies (%v internal, %v external, %v testing).\n",
+		countChange(d.Before.Total(), d.After.Total()),
+		countChange(d.Before.Internal, d.After.Internal),
+		countChange(d.Before.External, d.After.External),
+		countChange(d.Before.Testing, d.After.Testing))
+}
+
+// countChange formats the change between two counts.
+func countChange(before, after int) string {
+	if before == after {
+		return fmt.Sprint(after)
+	}
+	return fmt.Sprintf("%d -> %d (%+d)", before, after, after-before)
+}
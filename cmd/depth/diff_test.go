@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/KyleBanks/depth"
+)
+
+func Example_handleDiff() {
+	var t depth.Tree
+
+	handleDiff(&t, []string{"testdata/diff/old.json", "testdata/diff/new.json"})
+	// Output:
+	// + github.com/foo/db (github.com/foo/app -> github.com/foo/app/store -> github.com/foo/db)
+	// - github.com/foo/legacy (github.com/foo/app -> github.com/foo/legacy)
+	// ~ github.com/foo/log (github.com/foo/app -> github.com/foo/app/store -> github.com/foo/log => github.com/foo/app -> github.com/foo/app/store -> github.com/foo/db -> github.com/foo/log)
+	// 4 dependencies (1 internal, 3 external, 0 testing).
+}
+
+func Example_handleDiffSame() {
+	var t depth.Tree
+
+	handleDiff(&t, []string{"testdata/diff/old.json", "testdata/diff/old.json"})
+	// Output:
+	// 4 dependencies (1 internal, 3 external, 0 testing).
+}
+
+func Example_handleDiffDirs() {
+	var t depth.Tree
+
+	handleDiff(&t, []string{"testdata/rdeps/a", "testdata/rdeps/b"})
+	// Output:
+	// - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b (github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b)
+	// ~ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c (github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c => github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b -> github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c)
+	// 2 -> 1 (-1) dependencies (0 internal, 2 -> 1 (-1) external, 0 testing).
+}
+
+func Example_handleDiffUsage() {
+	var t depth.Tree
+
+	handleDiff(&t, []string{"testdata/diff/old.json"})
+	// Output:
+	// usage: depth diff [flags] <old> <new>
+}
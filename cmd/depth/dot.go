@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+// writeGraphDOT writes the Graph as a Graphviz digraph to the provided Writer.
+//
+// Internal, external, test-only and unresolved packages are styled differently,
+// and edges that are only used for testing are dashed. The nodes and edges of a
+// grouped Graph are labelled with the number of packages and imports they contain.
+func writeGraphDOT(w io.Writer, g *depth.Graph) {
+	fmt.Fprintln(w, "digraph depth {")
+	fmt.Fprintln(w, "  node [shape=box, style=rounded];")
+
+	roots := make(map[string]struct{})
+	for _, n := range g.Roots() {
+		roots[n.Name] = struct{}{}
+	}
+
+	for _, n := range g.Nodes() {
+		_, isRoot := roots[n.Name]
+		fmt.Fprintf(w, "  %v%v;\n", strconv.Quote(n.Name), dotAttrs(nodeAttrs(n, isRoot)))
+	}
+
+	for _, e := range g.Edges() {
+		fmt.Fprintf(w, "  %v -> %v%v;\n", strconv.Quote(e.From), strconv.Quote(e.To), dotAttrs(edgeAttrs(g, e)))
+	}
+
+	fmt.Fprintln(w, "}")
+}
+
+// nodeAttrs returns the DOT attributes used to style a Node.
+func nodeAttrs(n *depth.Node, isRoot bool) []string {
+	style := []string{"rounded"}
+	var attrs []string
+
+	if len(n.Packages) > 0 {
+		attrs = append(attrs, "label="+strconv.Quote(fmt.Sprintf("%v\n%d packages", n.Name, len(n.Packages))))
+	}
+	if isRoot {
+		attrs = append(attrs, "penwidth=2")
+	}
+	if n.Internal {
+		style = append(style, "filled")
+		attrs = append(attrs, `fillcolor="#eeeeee"`)
+	}
+	if n.Test {
+		style = append(style, "dashed")
+	}
+	if !n.Resolved {
+		attrs = append(attrs, "color=red", "fontcolor=red")
+	}
+
+	if len(style) > 1 {
+		attrs = append(attrs, "style="+strconv.Quote(strings.Join(style, ",")))
+	}
+	return attrs
+}
+
+// edgeAttrs returns the DOT attributes used to style an Edge.
+//
+// Edges that only apply to some of the platforms of the Graph are labelled with them.
+func edgeAttrs(g *depth.Graph, e depth.Edge) []string {
+	var labels []string
+	if len(e.Imports) > 0 {
+		labels = append(labels, fmt.Sprintf("%d imports", len(e.Imports)))
+	}
+	if len(e.Platforms) < len(g.Platforms()) {
+		labels = append(labels, e.Platforms...)
+	}
+
+	var attrs []string
+	if e.Test {
+		attrs = append(attrs, "style=dashed")
+		labels = append([]string{"test"}, labels...)
+	} else if from := g.Node(e.From); from != nil && from.Test {
+		attrs = append(attrs, "style=dashed")
+	}
+
+	if len(labels) > 0 {
+		attrs = append(attrs, "label="+strconv.Quote(strings.Join(labels, "\n")))
+	}
+	return attrs
+}
+
+// dotAttrs formats a list of DOT attributes.
+func dotAttrs(attrs []string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"go/build"
+	"os"
+
+	"github.com/KyleBanks/depth"
+)
+
+func Example_writeGraphDOT() {
+	t := depth.Tree{ResolveTest: true}
+	if err := t.Resolve("github.com/KyleBanks/depth/cmd/depth/testdata/dot"); err != nil {
+		panic(err)
+	}
+
+	writeGraphDOT(os.Stdout, t.Graph)
+	// Output:
+	// digraph depth {
+	//   node [shape=box, style=rounded];
+	//   "strings" [fillcolor="#eeeeee", style="rounded,filled"];
+	//   "testing" [fillcolor="#eeeeee", style="rounded,filled,dashed"];
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/dot" [penwidth=2];
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/dot/lib";
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/notreal" [color=red, fontcolor=red];
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/dot" -> "strings";
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/dot" -> "testing" [style=dashed, label="test"];
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/dot" -> "github.com/KyleBanks/depth/cmd/depth/testdata/dot/lib";
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/dot" -> "github.com/KyleBanks/depth/cmd/depth/testdata/notreal";
+	// }
+}
+
+func Example_writeGraphDOTPlatforms() {
+	ctx := build.Default
+	ctx.CgoEnabled = false
+	t := depth.Tree{Context: &ctx}
+	platforms := []depth.Platform{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "windows", GOARCH: "amd64"}}
+	if err := t.ResolvePlatforms(platforms, "github.com/KyleBanks/depth/cmd/depth/testdata/platforms"); err != nil {
+		panic(err)
+	}
+
+	writeGraphDOT(os.Stdout, t.Graph)
+	// Output:
+	// digraph depth {
+	//   node [shape=box, style=rounded];
+	//   "fmt" [fillcolor="#eeeeee", style="rounded,filled"];
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/platforms" [penwidth=2];
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/platforms/unix";
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/platforms/windows";
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/platforms" -> "fmt";
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/platforms" -> "github.com/KyleBanks/depth/cmd/depth/testdata/platforms/unix" [label="linux/amd64"];
+	//   "github.com/KyleBanks/depth/cmd/depth/testdata/platforms" -> "github.com/KyleBanks/depth/cmd/depth/testdata/platforms/windows" [label="windows/amd64"];
+	// }
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/KyleBanks/depth"
+)
+
+// groupJSON is the JSON representation of a Node of a grouped Graph.
+type groupJSON struct {
+	Name     string   `json:"name"`
+	Internal bool     `json:"internal"`
+	Resolved bool     `json:"resolved"`
+	Test     bool     `json:"test,omitempty"`
+	Packages []string `json:"packages"`
+}
+
+// groupEdgeJSON is the JSON representation of an Edge of a grouped Graph.
+type groupEdgeJSON struct {
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Test      bool     `json:"test,omitempty"`
+	Platforms []string `json:"platforms,omitempty"`
+	Imports   int      `json:"imports"`
+}
+
+// groupsJSON is the JSON representation of a grouped Graph.
+type groupsJSON struct {
+	Roots   []string        `json:"roots"`
+	Groups  []groupJSON     `json:"groups"`
+	Edges   []groupEdgeJSON `json:"edges"`
+	Summary depth.Summary   `json:"summary"`
+}
+
+// writeGroups collapses the Graph of the resolved Tree by the group of the options,
+// and writes it in the format of the options.
+func writeGroups(w io.Writer, t *depth.Tree, o options) {
+	g := t.Graph.Group(o.group)
+
+	switch o.format {
+	case formatJSON:
+		writeGroupsJSON(w, g)
+		return
+	case formatDOT:
+		writeGraphDOT(w, g)
+		return
+	}
+
+	if o.explain.target != "" {
+		for _, r := range g.Roots() {
+			writeExplain(w, g, r.Name, o.explain, false)
+		}
+		return
+	}
+
+	writeGraph(w, g, o)
+	writePkgSummary(w, g.Summary())
+	if err := t.Err(); err != nil {
+		fmt.Fprintln(w, err)
+	}
+}
+
+// writeGroupsJSON writes every group and the imports between them as JSON.
+func writeGroupsJSON(w io.Writer, g *depth.Graph) {
+	out := groupsJSON{
+		Groups:  []groupJSON{},
+		Edges:   []groupEdgeJSON{},
+		Summary: g.Summary(),
+	}
+
+	for _, n := range g.Roots() {
+		out.Roots = append(out.Roots, n.Name)
+	}
+	for _, n := range g.Nodes() {
+		group := groupJSON{Name: n.Name, Internal: n.Internal, Resolved: n.Resolved, Test: n.Test}
+		for _, p := range n.Packages {
+			group.Packages = append(group.Packages, p.Name)
+		}
+		out.Groups = append(out.Groups, group)
+	}
+	for _, e := range g.Edges() {
+		out.Edges = append(out.Edges, groupEdgeJSON{
+			From:      e.From,
+			To:        e.To,
+			Test:      e.Test,
+			Platforms: e.Platforms,
+			Imports:   len(e.Imports),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(out)
+}
+
+// groupSuffix formats the number of packages within a group, and the number of
+// imports collapsed into the Edge leading to it if provided, to follow the name of
+// the group. An empty string is returned for Nodes that aren't groups.
+func groupSuffix(n *depth.Node, e *depth.Edge) string {
+	if len(n.Packages) == 0 {
+		return ""
+	}
+	if e == nil {
+		return fmt.Sprintf(" (%d packages)", len(n.Packages))
+	}
+	return fmt.Sprintf(" (%d packages, %d imports)", len(n.Packages), len(e.Imports))
+}
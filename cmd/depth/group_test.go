@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"go/build"
+
+	"github.com/KyleBanks/depth"
+)
+
+func Example_handlePkgsGroupModule() {
+	handlePkgs(vendorTree("testdata/group/app"), []string{"./..."}, options{group: depth.GroupModule})
+	// Output:
+	// example.com/app (2 packages)
+	//   ├ std (1 packages, 1 imports)
+	//   ├ example.com/lib/v2 (2 packages, 1 imports)
+	//   ├ github.com/foo/bar (2 packages, 2 imports)
+	//   │ └ example.com/lib/v2 (2 packages, 1 imports)
+	//   └ github.com/foo/baz (1 packages, 1 imports)
+	//     └ example.com/lib/v2 (2 packages, 1 imports)
+	// 4 dependencies (1 internal, 3 external, 0 testing).
+}
+
+func Example_handlePkgsGroupModuleImporter() {
+	// Grouping by module resolves packages with go list, which reports their modules.
+	t := depth.Tree{Importer: &build.Default}
+
+	handlePkgs(&t, []string{"./testdata/rdeps/..."}, options{group: depth.GroupModule})
+	fmt.Printf("%T\n", t.Importer)
+	// Output:
+	// github.com/KyleBanks/depth (3 packages)
+	// 0 dependencies (0 internal, 0 external, 0 testing).
+	// *depth.GoListImporter
+}
+
+func Example_handlePkgsGroupRepo() {
+	handlePkgs(vendorTree("testdata/group/app"), []string{"./..."}, options{group: depth.GroupRepo})
+	// Output:
+	// example.com/app (2 packages)
+	//   ├ std (1 packages, 1 imports)
+	//   ├ example.com/lib (2 packages, 1 imports)
+	//   ├ github.com/foo/bar (2 packages, 2 imports)
+	//   │ └ example.com/lib (2 packages, 1 imports)
+	//   └ github.com/foo/baz (1 packages, 1 imports)
+	//     └ example.com/lib (2 packages, 1 imports)
+	// 4 dependencies (1 internal, 3 external, 0 testing).
+}
+
+func Example_handlePkgsGroupDomainJSON() {
+	handlePkgs(vendorTree("testdata/group/app"), []string{"./..."}, options{group: depth.GroupDomain, format: formatJSON})
+	// Output:
+	// {
+	//   "roots": [
+	//     "example.com"
+	//   ],
+	//   "groups": [
+	//     {
+	//       "name": "std",
+	//       "internal": true,
+	//       "resolved": true,
+	//       "packages": [
+	//         "strings"
+	//       ]
+	//     },
+	//     {
+	//       "name": "example.com",
+	//       "internal": false,
+	//       "resolved": true,
+	//       "packages": [
+	//         "example.com/app/cmd/app",
+	//         "example.com/app/internal/store",
+	//         "example.com/lib/v2",
+	//         "example.com/lib/v2/util"
+	//       ]
+	//     },
+	//     {
+	//       "name": "github.com",
+	//       "internal": false,
+	//       "resolved": true,
+	//       "packages": [
+	//         "github.com/foo/bar/a",
+	//         "github.com/foo/bar/b",
+	//         "github.com/foo/baz"
+	//       ]
+	//     }
+	//   ],
+	//   "edges": [
+	//     {
+	//       "from": "example.com",
+	//       "to": "std",
+	//       "imports": 1
+	//     },
+	//     {
+	//       "from": "example.com",
+	//       "to": "github.com",
+	//       "imports": 3
+	//     },
+	//     {
+	//       "from": "github.com",
+	//       "to": "example.com",
+	//       "imports": 2
+	//     }
+	//   ],
+	//   "summary": {
+	//     "internal": 1,
+	//     "external": 1,
+	//     "testing": 0
+	//   }
+	// }
+}
+
+func Example_handlePkgsGroupDOT() {
+	handlePkgs(vendorTree("testdata/group/app"), []string{"./cmd/app"}, options{group: depth.GroupRepo, format: formatDOT})
+	// Output:
+	// digraph depth {
+	//   node [shape=box, style=rounded];
+	//   "std" [label="std\n1 packages", fillcolor="#eeeeee", style="rounded,filled"];
+	//   "example.com/app" [label="example.com/app\n2 packages", penwidth=2];
+	//   "example.com/lib" [label="example.com/lib\n2 packages"];
+	//   "github.com/foo/bar" [label="github.com/foo/bar\n2 packages"];
+	//   "github.com/foo/baz" [label="github.com/foo/baz\n1 packages"];
+	//   "example.com/app" -> "std" [label="1 imports"];
+	//   "example.com/app" -> "example.com/lib" [label="1 imports"];
+	//   "example.com/app" -> "github.com/foo/bar" [label="2 imports"];
+	//   "example.com/app" -> "github.com/foo/baz" [label="1 imports"];
+	//   "github.com/foo/bar" -> "example.com/lib" [label="1 imports"];
+	//   "github.com/foo/baz" -> "example.com/lib" [label="1 imports"];
+	// }
+}
+
+func Example_handlePkgsGroupUnsupported() {
+	handlePkgs(vendorTree("testdata/group/app"), []string{"./..."}, options{group: depth.GroupModule, files: true})
+	// Output:
+	// FATAL: -group does not support -cycles, -tui, -watch, -files, -symbols or -sort=weight
+}
+
+func Example_handlePkgsGroupUnknown() {
+	handlePkgs(vendorTree("testdata/group/app"), []string{"./..."}, options{group: "package"})
+	// Output:
+	// FATAL: unknown group 'package', expected 'module', 'repo' or 'domain'
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KyleBanks/depth"
+)
+
+// handleLicense resolves the packages provided, which default to "./...", and writes
+// the license of each external dependency to Stdout, as a table of the packages and
+// modules using each license or, with the json and csv formats, as a report of every
+// package.
+func handleLicense(t *depth.Tree, args []string) error {
+	switch opts.format {
+	case "", formatText, formatJSON, formatCSV:
+	default:
+		err := fmt.Errorf("unknown format '%v', expected 'text', 'json' or 'csv'", opts.format)
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+
+	if len(args) == 0 {
+		args = []string{"./..."}
+	}
+
+	useModules(t)
+	if err := t.ResolveAll(args...); err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", strings.Join(args, " "), err)
+		return err
+	}
+
+	// License files are reported relative to the directory of the Tree.
+	licenses := t.Graph.Licenses()
+	dir, err := filepath.Abs(t.Dir)
+	if err != nil {
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+	for i, l := range licenses {
+		if rel, err := filepath.Rel(dir, l.File); err == nil && l.File != "" {
+			licenses[i].File = rel
+		}
+	}
+
+	switch opts.format {
+	case formatJSON:
+		writeLicensesJSON(os.Stdout, licenses)
+	case formatCSV:
+		writeLicensesCSV(os.Stdout, licenses)
+	default:
+		writeLicenses(os.Stdout, licenses)
+	}
+	return nil
+}
+
+// writeLicenses writes a table of the number of modules and packages using each
+// license, followed by the modules using each license.
+func writeLicenses(w io.Writer, licenses []depth.License) {
+	var ids []string
+	packages := make(map[string]int)
+	modules := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, l := range licenses {
+		if packages[l.SPDX] == 0 {
+			ids = append(ids, l.SPDX)
+		}
+		packages[l.SPDX]++
+
+		name := licenseModule(l)
+		if !seen[l.SPDX+" "+name] {
+			seen[l.SPDX+" "+name] = true
+			modules[l.SPDX] = append(modules[l.SPDX], name)
+		}
+	}
+	sort.Strings(ids)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LICENSE\tMODULES\tPACKAGES")
+	for _, id := range ids {
+		fmt.Fprintf(tw, "%v\t%d\t%d\n", id, len(modules[id]), packages[id])
+	}
+	tw.Flush()
+
+	for _, id := range ids {
+		sort.Strings(modules[id])
+		fmt.Fprintf(w, "\n%v\n", id)
+		for _, m := range modules[id] {
+			fmt.Fprintf(w, "%v%v\n", outputClosedPadding, m)
+		}
+	}
+}
+
+// licenseModule returns the module of a License, or its package if the module is
+// unknown.
+func licenseModule(l depth.License) string {
+	if l.Module == nil {
+		return l.Package
+	}
+	return l.Module.String()
+}
+
+// writeLicensesJSON writes every License as a JSON array.
+func writeLicensesJSON(w io.Writer, licenses []depth.License) {
+	if licenses == nil {
+		licenses = []depth.License{}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.Encode(licenses)
+}
+
+// writeLicensesCSV writes every License as a CSV row, following a header.
+func writeLicensesCSV(w io.Writer, licenses []depth.License) {
+	c := csv.NewWriter(w)
+	c.Write([]string{"package", "module", "version", "license", "file"})
+	for _, l := range licenses {
+		var module, version string
+		if l.Module != nil {
+			module, version = l.Module.Path, l.Module.Version
+		}
+		c.Write([]string{l.Package, module, version, l.SPDX, l.File})
+	}
+	c.Flush()
+}
@@ -0,0 +1,75 @@
+package main
+
+func Example_handleLicense() {
+	opts = options{}
+
+	handleLicense(vendorTree("testdata/license/app"), nil)
+	// Output:
+	// LICENSE       MODULES  PACKAGES
+	// BSD-3-Clause  1        1
+	// MIT           1        1
+	// None          1        1
+	//
+	// BSD-3-Clause
+	//   example.com/bsd v1.1.0
+	//
+	// MIT
+	//   example.com/mit v1.0.0
+	//
+	// None
+	//   example.com/none v0.1.0
+}
+
+func Example_handleLicenseJSON() {
+	opts = options{format: formatJSON}
+
+	handleLicense(vendorTree("testdata/license/app"), []string{"./cmd/..."})
+	// Output:
+	// [
+	//   {
+	//     "package": "example.com/bsd/sub",
+	//     "module": {
+	//       "path": "example.com/bsd",
+	//       "version": "v1.1.0"
+	//     },
+	//     "spdx": "BSD-3-Clause",
+	//     "file": "vendor/example.com/bsd/LICENSE"
+	//   },
+	//   {
+	//     "package": "example.com/mit",
+	//     "module": {
+	//       "path": "example.com/mit",
+	//       "version": "v1.0.0"
+	//     },
+	//     "spdx": "MIT",
+	//     "file": "vendor/example.com/mit/LICENSE"
+	//   },
+	//   {
+	//     "package": "example.com/none",
+	//     "module": {
+	//       "path": "example.com/none",
+	//       "version": "v0.1.0"
+	//     },
+	//     "spdx": "None"
+	//   }
+	// ]
+}
+
+func Example_handleLicenseCSV() {
+	opts = options{format: formatCSV}
+
+	handleLicense(vendorTree("testdata/license/app"), nil)
+	// Output:
+	// package,module,version,license,file
+	// example.com/bsd/sub,example.com/bsd,v1.1.0,BSD-3-Clause,vendor/example.com/bsd/LICENSE
+	// example.com/mit,example.com/mit,v1.0.0,MIT,vendor/example.com/mit/LICENSE
+	// example.com/none,example.com/none,v0.1.0,None,
+}
+
+func Example_handleLicenseUnknownFormat() {
+	opts = options{format: formatDOT}
+
+	handleLicense(vendorTree("testdata/license/app"), nil)
+	// Output:
+	// FATAL: unknown format 'dot', expected 'text', 'json' or 'csv'
+}
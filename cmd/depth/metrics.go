@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KyleBanks/depth"
+)
+
+// The orders of the metrics command, in addition to sortName. Each puts the packages
+// with the highest value first.
+const (
+	sortAfferent    = "ca"
+	sortEfferent    = "ce"
+	sortInstability = "instability"
+	sortFanIn       = "fan-in"
+	sortFanOut      = "fan-out"
+	sortDepth       = "depth"
+)
+
+// metricsSorts contains a function returning the value of the metric used by each
+// order of the metrics command.
+var metricsSorts = map[string]func(m depth.Metrics) float64{
+	sortAfferent:    func(m depth.Metrics) float64 { return float64(m.Afferent) },
+	sortEfferent:    func(m depth.Metrics) float64 { return float64(m.Efferent) },
+	sortInstability: func(m depth.Metrics) float64 { return m.Instability },
+	sortFanIn:       func(m depth.Metrics) float64 { return float64(m.FanIn) },
+	sortFanOut:      func(m depth.Metrics) float64 { return float64(m.FanOut) },
+	sortDepth:       func(m depth.Metrics) float64 { return float64(m.Depth) },
+}
+
+// handleMetrics resolves the packages provided, which default to "./...", and writes
+// the coupling metrics of each package to Stdout, as a table or, with the json and
+// csv formats, as a report of every package. Internal packages are only included
+// when their dependencies are resolved with -internal.
+func handleMetrics(t *depth.Tree, args []string) error {
+	switch opts.format {
+	case "", formatText, formatJSON, formatCSV:
+	default:
+		err := fmt.Errorf("unknown format '%v', expected 'text', 'json' or 'csv'", opts.format)
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+
+	if _, ok := metricsSorts[opts.sort]; !ok && opts.sort != "" && opts.sort != sortName {
+		err := fmt.Errorf("unknown sort '%v', expected 'name', 'ca', 'ce', 'instability', 'fan-in', 'fan-out' or 'depth'", opts.sort)
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+
+	if len(args) == 0 {
+		args = []string{"./..."}
+	}
+	if err := t.ResolveAll(args...); err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", strings.Join(args, " "), err)
+		return err
+	}
+
+	var metrics []depth.Metrics
+	for _, m := range t.Graph.Metrics() {
+		if !m.Internal || t.ResolveInternal {
+			metrics = append(metrics, m)
+		}
+	}
+	sortMetrics(metrics, opts.sort)
+
+	switch opts.format {
+	case formatJSON:
+		writeMetricsJSON(os.Stdout, metrics)
+	case formatCSV:
+		writeMetricsCSV(os.Stdout, metrics)
+	default:
+		writeMetrics(os.Stdout, metrics)
+	}
+	return nil
+}
+
+// sortMetrics sorts the Metrics in the order provided, keeping the order of the Graph
+// for packages with the same value.
+func sortMetrics(metrics []depth.Metrics, order string) {
+	value, ok := metricsSorts[order]
+	if !ok {
+		return
+	}
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		return value(metrics[i]) > value(metrics[j])
+	})
+}
+
+// writeMetrics writes the Metrics of each package as a table.
+func writeMetrics(w io.Writer, metrics []depth.Metrics) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PACKAGE\tCA\tCE\tINSTABILITY\tFAN-IN\tFAN-OUT\tDEPTH")
+	for _, m := range metrics {
+		fmt.Fprintf(tw, "%v\t%d\t%d\t%.2f\t%d\t%d\t%d\n", m.Name, m.Afferent, m.Efferent, m.Instability, m.FanIn, m.FanOut, m.Depth)
+	}
+	tw.Flush()
+}
+
+// writeMetricsJSON writes the Metrics of each package as a JSON array.
+func writeMetricsJSON(w io.Writer, metrics []depth.Metrics) {
+	if metrics == nil {
+		metrics = []depth.Metrics{}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.Encode(metrics)
+}
+
+// writeMetricsCSV writes the Metrics of each package as a CSV row, following a header.
+func writeMetricsCSV(w io.Writer, metrics []depth.Metrics) {
+	c := csv.NewWriter(w)
+	c.Write([]string{"package", "internal", "ca", "ce", "instability", "fan_in", "fan_out", "depth"})
+	for _, m := range metrics {
+		c.Write([]string{
+			m.Name,
+			strconv.FormatBool(m.Internal),
+			strconv.Itoa(m.Afferent),
+			strconv.Itoa(m.Efferent),
+			strconv.FormatFloat(m.Instability, 'f', 4, 64),
+			strconv.Itoa(m.FanIn),
+			strconv.Itoa(m.FanOut),
+			strconv.Itoa(m.Depth),
+		})
+	}
+	c.Flush()
+}
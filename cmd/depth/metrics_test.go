@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/KyleBanks/depth"
+)
+
+func Example_handleMetrics() {
+	var t depth.Tree
+	opts = options{}
+
+	handleMetrics(&t, []string{"./testdata/layers/handlers"})
+	// Output:
+	// PACKAGE                                                               CA  CE  INSTABILITY  FAN-IN  FAN-OUT  DEPTH
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/handlers         0   2   1.00         0       4        0
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/models           1   1   0.50         3       1        2
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/repositories     2   1   0.33         2       2        1
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/services         1   1   0.50         1       3        1
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/services/format  1   0   0.00         4       0        3
+}
+
+func Example_handleMetricsSort() {
+	var t depth.Tree
+	opts = options{sort: sortInstability}
+
+	handleMetrics(&t, []string{"./testdata/layers/..."})
+	// Output:
+	// PACKAGE                                                               CA  CE  INSTABILITY  FAN-IN  FAN-OUT  DEPTH
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/handlers         0   2   1.00         0       4        0
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/models           1   1   0.50         3       1        2
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/services         1   1   0.50         1       3        1
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/repositories     2   1   0.33         2       2        1
+	// github.com/KyleBanks/depth/cmd/depth/testdata/layers/services/format  1   0   0.00         4       0        3
+}
+
+func Example_handleMetricsCSV() {
+	var t depth.Tree
+	opts = options{format: formatCSV, sort: sortFanIn}
+
+	handleMetrics(&t, []string{"./testdata/rdeps/a"})
+	// Output:
+	// package,internal,ca,ce,instability,fan_in,fan_out,depth
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c,false,1,0,0.0000,2,0,2
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b,false,1,1,0.5000,1,1,1
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a,false,0,1,1.0000,0,2,0
+}
+
+func Example_handleMetricsUnknownSort() {
+	var t depth.Tree
+	opts = options{sort: sortWeight}
+
+	handleMetrics(&t, nil)
+	// Output:
+	// FATAL: unknown sort 'weight', expected 'name', 'ca', 'ce', 'instability', 'fan-in', 'fan-out' or 'depth'
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/KyleBanks/depth"
+)
+
+// errNoTarget is returned when the rdeps command is run without a target package.
+var errNoTarget = errors.New("usage: depth rdeps [flags] <target> [patterns...]")
+
+// rdeps contains the packages that import a target package.
+type rdeps struct {
+	target     string
+	scanned    int
+	direct     []string
+	transitive []string
+}
+
+// handleRdeps resolves every package matching the patterns provided, which default
+// to "./...", and writes the packages that import the target directly and
+// transitively to Stdout.
+func handleRdeps(t *depth.Tree, args []string) error {
+	if len(args) == 0 {
+		fmt.Println(errNoTarget)
+		return errNoTarget
+	}
+
+	target, patterns := args[0], args[1:]
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	r, err := findRdeps(t, target, patterns)
+	if err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", target, err)
+		return err
+	}
+
+	writeRdeps(os.Stdout, r)
+	return nil
+}
+
+// findRdeps resolves every package matching the patterns provided, and collects
+// those that import the target.
+func findRdeps(t *depth.Tree, target string, patterns []string) (*rdeps, error) {
+	// Resolve the target to find its fully qualified import path.
+	if names, err := t.Expand(target); err == nil && len(names) == 1 {
+		target = names[0]
+	}
+	if err := t.Resolve(target); err != nil {
+		return nil, err
+	}
+	r := rdeps{target: t.Root.Name}
+
+	// The stdlib is only reached through the imports of other stdlib packages when
+	// they are resolved.
+	if t.Root.Internal {
+		t.ResolveInternal = true
+	}
+
+	if err := t.ResolveAll(patterns...); err != nil {
+		return nil, err
+	}
+
+	for _, root := range t.Roots {
+		if !root.Resolved {
+			fmt.Fprintf(os.Stderr, "'%v': %v\n", root.Name, root.Err)
+			continue
+		}
+
+		r.scanned++
+		name := root.Name
+		if name == r.target || t.Graph.ShortestPath(name, r.target) == nil {
+			continue
+		}
+
+		if importsDirectly(t.Graph, name, r.target) {
+			r.direct = append(r.direct, name)
+		} else {
+			r.transitive = append(r.transitive, name)
+		}
+	}
+
+	return &r, nil
+}
+
+// importsDirectly returns true if the package provided directly imports the target.
+func importsDirectly(g *depth.Graph, name, target string) bool {
+	for _, n := range g.ImportsOf(name) {
+		if n.Name == target {
+			return true
+		}
+	}
+	return false
+}
+
+// writeRdeps writes the direct and transitive importers of a package to the Writer.
+func writeRdeps(w io.Writer, r *rdeps) {
+	fmt.Fprintf(w, "%v\n", r.target)
+
+	importers := append(append([]string{}, r.direct...), r.transitive...)
+	for idx, name := range importers {
+		prefix, kind := outputClosedPadding+outputPrefix, "direct"
+		if idx == len(importers)-1 {
+			prefix = outputClosedPadding + outputPrefixLast
+		}
+		if idx >= len(r.direct) {
+			kind = "transitive"
+		}
+
+		fmt.Fprintf(w, "%v%v (%v)\n", prefix, name, kind)
+	}
+
+	fmt.Fprintf(w, "%d of %d packages import %v (%d direct, %d transitive).\n",
+		len(r.direct)+len(r.transitive),
+		r.scanned,
+		r.target,
+		len(r.direct),
+		len(r.transitive))
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/KyleBanks/depth"
+)
+
+func Example_handleRdeps() {
+	var t depth.Tree
+
+	handleRdeps(&t, []string{"./testdata/rdeps/c", "./testdata/rdeps/..."})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	//   ├ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b (direct)
+	//   └ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a (transitive)
+	// 2 of 3 packages import github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c (1 direct, 1 transitive).
+}
+
+func Example_handleRdepsNone() {
+	var t depth.Tree
+
+	handleRdeps(&t, []string{"./testdata/rdeps/a", "./testdata/rdeps/..."})
+	// Output:
+	// github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 0 of 3 packages import github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a (0 direct, 0 transitive).
+}
+
+func Example_handleRdepsNoTarget() {
+	var t depth.Tree
+
+	handleRdeps(&t, nil)
+	// Output:
+	// usage: depth rdeps [flags] <target> [patterns...]
+}
+
+func Example_handleRdepsInternal() {
+	t := depth.Tree{
+		Dir:      "testdata/group/app",
+		Importer: &depth.GoListImporter{Env: []string{"GOFLAGS=-mod=vendor"}},
+	}
+
+	handleRdeps(&t, []string{"unicode/utf8", "./..."})
+	// Output:
+	// unicode/utf8
+	//   ├ example.com/app/cmd/app (transitive)
+	//   └ example.com/app/internal/store (transitive)
+	// 2 of 2 packages import unicode/utf8 (0 direct, 2 transitive).
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+// static contains the page served by the serve command, which has no external
+// assets so that it works offline.
+//
+//go:embed static
+var static embed.FS
+
+// handleServe resolves every package matching the patterns provided, which default
+// to "./...", and serves the resulting Graph over HTTP until the server fails.
+func handleServe(t *depth.Tree, args []string) error {
+	if len(args) == 0 {
+		args = []string{"./..."}
+	}
+
+	if err := t.ResolveAll(args...); err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", strings.Join(args, " "), err)
+		return err
+	}
+
+	fmt.Printf("Serving %d packages on http://%v\n", len(t.Graph.Nodes()), opts.addr)
+	if err := http.ListenAndServe(opts.addr, newServer(t)); err != nil {
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// server serves the static page and the JSON API used to explore a resolved Tree.
+type server struct {
+	t   *depth.Tree
+	mux *http.ServeMux
+}
+
+// nodeJSON is the JSON representation of a depth.Node.
+type nodeJSON struct {
+	Name     string              `json:"name"`
+	Internal bool                `json:"internal"`
+	Resolved bool                `json:"resolved"`
+	Test     bool                `json:"test,omitempty"`
+	Module   *depth.Module       `json:"module,omitempty"`
+	Err      *depth.ResolveError `json:"error,omitempty"`
+}
+
+// edgeJSON is the JSON representation of a depth.Edge.
+type edgeJSON struct {
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+	Test      bool             `json:"test,omitempty"`
+	Platforms []string         `json:"platforms,omitempty"`
+	Files     []depth.Position `json:"files,omitempty"`
+}
+
+// graphJSON is the response of the graph API.
+type graphJSON struct {
+	Roots     []string      `json:"roots"`
+	Platforms []string      `json:"platforms,omitempty"`
+	Nodes     []nodeJSON    `json:"nodes"`
+	Edges     []edgeJSON    `json:"edges"`
+	Summary   depth.Summary `json:"summary"`
+}
+
+// packageJSON is the response of the package API.
+type packageJSON struct {
+	nodeJSON
+	Weight           depth.Weight `json:"weight"`
+	TransitiveWeight depth.Weight `json:"transitive_weight"`
+	Imports          []edgeJSON   `json:"imports"`
+	Importers        []edgeJSON   `json:"importers"`
+}
+
+// maxExplainPaths is the most paths the explain API returns from each package in
+// the all mode, as their number can grow exponentially with the size of the Graph.
+const maxExplainPaths = 100
+
+// explainJSON is the response of the explain API.
+type explainJSON struct {
+	Paths [][]string `json:"paths"`
+}
+
+// newServer returns the HTTP handler of the serve command for a resolved Tree.
+func newServer(t *depth.Tree) http.Handler {
+	s := server{t: t, mux: http.NewServeMux()}
+
+	root, _ := fs.Sub(static, "static")
+	s.mux.Handle("/", http.FileServer(http.FS(root)))
+	s.mux.HandleFunc("/api/graph", s.handleGraph)
+	s.mux.HandleFunc("/api/package", s.handlePackage)
+	s.mux.HandleFunc("/api/explain", s.handleExplain)
+	return s.mux
+}
+
+// handleGraph writes every Node and Edge of the Graph.
+func (s *server) handleGraph(w http.ResponseWriter, r *http.Request) {
+	g := s.t.Graph
+	res := graphJSON{
+		Platforms: g.Platforms(),
+		Nodes:     []nodeJSON{},
+		Edges:     []edgeJSON{},
+		Summary:   g.Summary(),
+	}
+
+	for _, n := range g.Roots() {
+		res.Roots = append(res.Roots, n.Name)
+	}
+	for _, n := range g.Nodes() {
+		res.Nodes = append(res.Nodes, newNodeJSON(n))
+	}
+	for _, e := range g.Edges() {
+		res.Edges = append(res.Edges, newEdgeJSON(e))
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// handlePackage writes a single Node, its weight and its imports and importers.
+func (s *server) handlePackage(w http.ResponseWriter, r *http.Request) {
+	g := s.t.Graph
+	name := r.URL.Query().Get("name")
+	n := g.Node(name)
+	if n == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown package '%v'", name))
+		return
+	}
+
+	res := packageJSON{
+		nodeJSON:         newNodeJSON(n),
+		Weight:           g.Weight(name),
+		TransitiveWeight: g.TransitiveWeight(name),
+		Imports:          []edgeJSON{},
+		Importers:        []edgeJSON{},
+	}
+	for _, e := range g.EdgesFrom(name) {
+		res.Imports = append(res.Imports, newEdgeJSON(e))
+	}
+	for _, i := range g.ImportersOf(name) {
+		for _, e := range g.EdgesFrom(i.Name) {
+			if e.To == name {
+				res.Importers = append(res.Importers, newEdgeJSON(e))
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// handleExplain writes the paths from a package, or each root if none is provided,
+// to the target package. Only the shortest path from each package is written unless
+// the mode is "all", in which case the max parameter can further limit the number of
+// paths below maxExplainPaths.
+func (s *server) handleExplain(w http.ResponseWriter, r *http.Request) {
+	g := s.t.Graph
+	q := r.URL.Query()
+
+	e := explainer{target: q.Get("to"), mode: q.Get("mode")}
+	if e.mode == "" {
+		e.mode = explainShortest
+	}
+	if e.mode != explainShortest && e.mode != explainAll {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown explain mode '%v', expected 'all' or 'shortest'", e.mode))
+		return
+	}
+	e.max = maxExplainPaths
+	if max := q.Get("max"); max != "" {
+		var err error
+		if e.max, err = strconv.Atoi(max); err != nil || e.max <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid max '%v'", max))
+			return
+		}
+		if e.max > maxExplainPaths {
+			e.max = maxExplainPaths
+		}
+	}
+
+	if g.Node(e.target) == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown package '%v'", e.target))
+		return
+	}
+
+	var from []string
+	if f := q.Get("from"); f != "" {
+		from = []string{f}
+	} else {
+		for _, n := range g.Roots() {
+			from = append(from, n.Name)
+		}
+	}
+
+	res := explainJSON{Paths: [][]string{}}
+	for _, f := range from {
+		if e.mode == explainShortest {
+			if p := g.ShortestPath(f, e.target); p != nil {
+				res.Paths = append(res.Paths, p)
+			}
+			continue
+		}
+		res.Paths = append(res.Paths, g.AllPaths(f, e.target, e.max)...)
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// newNodeJSON returns the JSON representation of the Node.
+func newNodeJSON(n *depth.Node) nodeJSON {
+	return nodeJSON{
+		Name:     n.Name,
+		Internal: n.Internal,
+		Resolved: n.Resolved,
+		Test:     n.Test,
+		Module:   n.Pkg.Module,
+		Err:      n.Pkg.Err,
+	}
+}
+
+// newEdgeJSON returns the JSON representation of the Edge.
+func newEdgeJSON(e depth.Edge) edgeJSON {
+	return edgeJSON{
+		From:      e.From,
+		To:        e.To,
+		Test:      e.Test,
+		Platforms: e.Platforms,
+		Files:     e.Files,
+	}
+}
+
+// writeJSON writes the value as the JSON response, with the status code provided.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.Encode(v)
+}
+
+// writeError writes the error as a JSON response, with the status code provided.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
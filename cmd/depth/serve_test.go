@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KyleBanks/depth"
+)
+
+// serveRequest resolves the rdeps test packages and writes the response of the server
+// to a GET request for the URL provided.
+func serveRequest(url string) {
+	var t depth.Tree
+	if err := t.ResolveAll("./testdata/rdeps/..."); err != nil {
+		panic(err)
+	}
+
+	w := httptest.NewRecorder()
+	newServer(&t).ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	fmt.Println(w.Code)
+	fmt.Print(w.Body.String())
+}
+
+func Example_serverGraph() {
+	serveRequest("/api/graph")
+	// Output:
+	// 200
+	// {
+	//   "roots": [
+	//     "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a",
+	//     "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//     "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c"
+	//   ],
+	//   "nodes": [
+	//     {
+	//       "name": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a",
+	//       "internal": false,
+	//       "resolved": true
+	//     },
+	//     {
+	//       "name": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//       "internal": false,
+	//       "resolved": true
+	//     },
+	//     {
+	//       "name": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c",
+	//       "internal": false,
+	//       "resolved": true
+	//     }
+	//   ],
+	//   "edges": [
+	//     {
+	//       "from": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a",
+	//       "to": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//       "files": [
+	//         {
+	//           "file": "a.go",
+	//           "line": 5
+	//         }
+	//       ]
+	//     },
+	//     {
+	//       "from": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//       "to": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c",
+	//       "files": [
+	//         {
+	//           "file": "b.go",
+	//           "line": 5
+	//         }
+	//       ]
+	//     }
+	//   ],
+	//   "summary": {
+	//     "internal": 0,
+	//     "external": 0,
+	//     "testing": 0
+	//   }
+	// }
+}
+
+func Example_serverPackage() {
+	serveRequest("/api/package?name=github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b")
+	// Output:
+	// 200
+	// {
+	//   "name": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//   "internal": false,
+	//   "resolved": true,
+	//   "weight": {
+	//     "files": 1,
+	//     "lines": 9,
+	//     "symbols": 1
+	//   },
+	//   "transitive_weight": {
+	//     "files": 2,
+	//     "lines": 14,
+	//     "symbols": 2
+	//   },
+	//   "imports": [
+	//     {
+	//       "from": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//       "to": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c",
+	//       "files": [
+	//         {
+	//           "file": "b.go",
+	//           "line": 5
+	//         }
+	//       ]
+	//     }
+	//   ],
+	//   "importers": [
+	//     {
+	//       "from": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a",
+	//       "to": "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//       "files": [
+	//         {
+	//           "file": "a.go",
+	//           "line": 5
+	//         }
+	//       ]
+	//     }
+	//   ]
+	// }
+}
+
+func Example_serverPackageUnknown() {
+	serveRequest("/api/package?name=notreal")
+	// Output:
+	// 404
+	// {
+	//   "error": "unknown package 'notreal'"
+	// }
+}
+
+func Example_serverExplain() {
+	serveRequest("/api/explain?to=github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c")
+	// Output:
+	// 200
+	// {
+	//   "paths": [
+	//     [
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a",
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c"
+	//     ],
+	//     [
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c"
+	//     ],
+	//     [
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c"
+	//     ]
+	//   ]
+	// }
+}
+
+func Example_serverExplainAll() {
+	serveRequest("/api/explain?to=github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c&from=github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a&mode=all&max=1")
+	// Output:
+	// 200
+	// {
+	//   "paths": [
+	//     [
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a",
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+	//       "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c"
+	//     ]
+	//   ]
+	// }
+}
+
+func Example_serverExplainUnknownMode() {
+	serveRequest("/api/explain?to=github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c&mode=longest")
+	// Output:
+	// 400
+	// {
+	//   "error": "unknown explain mode 'longest', expected 'all' or 'shortest'"
+	// }
+}
+
+func Test_serverIndex(t *testing.T) {
+	var tr depth.Tree
+	if err := tr.Resolve("strings"); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	newServer(&tr).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != 200 {
+		t.Fatalf("Unexpected status, expected=%v, got=%v", 200, w.Code)
+	} else if !strings.Contains(w.Body.String(), "/api/graph") {
+		t.Fatal("Expected the page to load the graph API")
+	} else if strings.Contains(w.Body.String(), `src="http`) || strings.Contains(w.Body.String(), `href="http`) {
+		t.Fatal("Expected the page to have no external assets")
+	}
+}
+
+func Example_serverExplainInvalidMax() {
+	serveRequest("/api/explain?to=github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c&mode=all&max=0")
+	// Output:
+	// 400
+	// {
+	//   "error": "invalid max '0'"
+	// }
+}
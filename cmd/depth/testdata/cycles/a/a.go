@@ -0,0 +1,5 @@
+// Package a is imported by package b, and imports it from its external tests.
+package a
+
+// Name is the name of the package.
+const Name = "a"
@@ -0,0 +1,13 @@
+package a_test
+
+import (
+	"testing"
+
+	"github.com/KyleBanks/depth/cmd/depth/testdata/cycles/b"
+)
+
+func TestName(t *testing.T) {
+	if b.Name != "ba" {
+		t.Fatal(b.Name)
+	}
+}
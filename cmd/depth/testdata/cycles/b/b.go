@@ -0,0 +1,9 @@
+// Package b imports package a.
+package b
+
+import (
+	"github.com/KyleBanks/depth/cmd/depth/testdata/cycles/a"
+)
+
+// Name is the name of the package.
+const Name = "b" + a.Name
@@ -0,0 +1,11 @@
+// Package dot is used to test the DOT output of depth.
+package dot
+
+import (
+	"strings"
+
+	"github.com/KyleBanks/depth/cmd/depth/testdata/dot/lib"
+	_ "github.com/KyleBanks/depth/cmd/depth/testdata/notreal"
+)
+
+var _ = strings.ToUpper(lib.Name)
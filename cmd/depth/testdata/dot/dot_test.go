@@ -0,0 +1,7 @@
+package dot
+
+import (
+	"testing"
+)
+
+func TestDot(t *testing.T) {}
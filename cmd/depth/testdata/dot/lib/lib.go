@@ -0,0 +1,5 @@
+// Package lib is imported by the dot test package.
+package lib
+
+// Name is the name of the package.
+const Name = "lib"
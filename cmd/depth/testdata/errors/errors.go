@@ -0,0 +1,8 @@
+// Package errors imports packages that cannot be resolved.
+package errors
+
+import (
+	_ "github.com/KyleBanks/depth/cmd/depth/testdata/errors/constrained"
+	_ "github.com/KyleBanks/depth/cmd/depth/testdata/errors/invalid"
+	_ "github.com/KyleBanks/depth/cmd/depth/testdata/notreal"
+)
@@ -0,0 +1,4 @@
+package invalid
+
+import (
+	"strings"
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"example.com/app/internal/store"
+	"github.com/foo/bar/a"
+	"github.com/foo/bar/b"
+	"github.com/foo/baz"
+)
+
+func main() {
+	store.Open()
+	a.Run()
+	b.Run()
+	baz.Run()
+}
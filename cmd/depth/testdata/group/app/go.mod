@@ -0,0 +1,9 @@
+module example.com/app
+
+go 1.17
+
+require (
+	example.com/lib/v2 v2.0.1
+	github.com/foo/bar v1.3.0
+	github.com/foo/baz v0.2.0
+)
@@ -0,0 +1,9 @@
+package store
+
+import (
+	"strings"
+
+	"example.com/lib/v2"
+)
+
+func Open() string { return strings.ToUpper(lib.Name) }
@@ -0,0 +1,12 @@
+// Package handlers skips the services layer by importing repositories.
+package handlers
+
+import (
+	"github.com/KyleBanks/depth/cmd/depth/testdata/layers/repositories"
+	"github.com/KyleBanks/depth/cmd/depth/testdata/layers/services"
+)
+
+func Handle() {
+	services.Serve()
+	repositories.Find()
+}
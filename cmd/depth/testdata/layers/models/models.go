@@ -0,0 +1,6 @@
+// Package models imports upward from the services layer.
+package models
+
+import "github.com/KyleBanks/depth/cmd/depth/testdata/layers/services/format"
+
+func New() string { return format.Name("model") }
@@ -0,0 +1,5 @@
+package repositories
+
+import "github.com/KyleBanks/depth/cmd/depth/testdata/layers/models"
+
+func Find() { models.New() }
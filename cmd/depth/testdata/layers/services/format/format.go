@@ -0,0 +1,3 @@
+package format
+
+func Name(s string) string { return s }
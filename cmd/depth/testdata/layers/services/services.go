@@ -0,0 +1,5 @@
+package services
+
+import "github.com/KyleBanks/depth/cmd/depth/testdata/layers/repositories"
+
+func Serve() { repositories.Find() }
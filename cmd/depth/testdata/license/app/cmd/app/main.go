@@ -0,0 +1,9 @@
+package main
+
+import (
+	_ "example.com/bsd/sub"
+	_ "example.com/mit"
+	_ "example.com/none"
+)
+
+func main() {}
@@ -0,0 +1,9 @@
+module example.com/app
+
+go 1.17
+
+require (
+	example.com/bsd v1.1.0
+	example.com/mit v1.0.0
+	example.com/none v0.1.0
+)
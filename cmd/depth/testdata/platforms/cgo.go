@@ -0,0 +1,10 @@
+//go:build cgo
+// +build cgo
+
+package platforms
+
+import (
+	"github.com/KyleBanks/depth/cmd/depth/testdata/platforms/cgo"
+)
+
+var _ = cgo.Name
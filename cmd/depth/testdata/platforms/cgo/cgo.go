@@ -0,0 +1,5 @@
+// Package cgo is only imported by some builds of the platforms package.
+package cgo
+
+// Name is the name of the package.
+const Name = "cgo"
@@ -0,0 +1,10 @@
+//go:build custom
+// +build custom
+
+package platforms
+
+import (
+	"github.com/KyleBanks/depth/cmd/depth/testdata/platforms/custom"
+)
+
+var _ = custom.Name
@@ -0,0 +1,5 @@
+// Package custom is only imported by some builds of the platforms package.
+package custom
+
+// Name is the name of the package.
+const Name = "custom"
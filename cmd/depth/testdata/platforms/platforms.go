@@ -0,0 +1,9 @@
+// Package platforms imports different packages depending on the target platform,
+// build tags and cgo.
+package platforms
+
+import (
+	"fmt"
+)
+
+var _ = fmt.Sprint
@@ -0,0 +1,7 @@
+package platforms
+
+import (
+	"github.com/KyleBanks/depth/cmd/depth/testdata/platforms/windows"
+)
+
+var _ = windows.Name
@@ -0,0 +1,5 @@
+// Package unix is only imported by some builds of the platforms package.
+package unix
+
+// Name is the name of the package.
+const Name = "unix"
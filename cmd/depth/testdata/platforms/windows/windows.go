@@ -0,0 +1,5 @@
+// Package windows is only imported by some builds of the platforms package.
+package windows
+
+// Name is the name of the package.
+const Name = "windows"
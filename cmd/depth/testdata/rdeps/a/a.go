@@ -0,0 +1,8 @@
+// Package a transitively imports package c.
+package a
+
+import (
+	"github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b"
+)
+
+var _ = b.Name
@@ -0,0 +1,9 @@
+// Package b directly imports package c.
+package b
+
+import (
+	"github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c"
+)
+
+// Name is the name of the package.
+const Name = "b" + c.Name
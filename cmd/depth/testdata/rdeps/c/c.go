@@ -0,0 +1,5 @@
+// Package c is imported by the other rdeps test packages.
+package c
+
+// Name is the name of the package.
+const Name = "c"
@@ -0,0 +1,21 @@
+// Package app uses a single function of package narrow, several identifiers of
+// package wide, and imports package side for its side effects.
+package app
+
+import (
+	"strings"
+
+	"github.com/KyleBanks/depth/cmd/depth/testdata/symbols/narrow"
+	_ "github.com/KyleBanks/depth/cmd/depth/testdata/symbols/side"
+	"github.com/KyleBanks/depth/cmd/depth/testdata/symbols/wide"
+)
+
+// Name joins the names of each package.
+func Name() string {
+	var b strings.Builder
+	b.WriteString(narrow.Name())
+
+	var c wide.Counter
+	c.Add(wide.Step)
+	return strings.Join([]string{b.String(), wide.Name}, c.String())
+}
@@ -0,0 +1,8 @@
+// Package narrow is only used for a single function.
+package narrow
+
+// Name returns the name of the package.
+func Name() string { return "narrow" }
+
+// Unused isn't referenced by package app.
+func Unused() {}
@@ -0,0 +1,4 @@
+// Package side is only imported for its side effects.
+package side
+
+func init() {}
@@ -0,0 +1,21 @@
+// Package wide is used for several identifiers.
+package wide
+
+import "strconv"
+
+// Name is the name of the package.
+const Name = "wide"
+
+// Step is the amount added to a Counter.
+var Step = 1
+
+// Counter counts.
+type Counter struct {
+	N int
+}
+
+// Add adds to the Counter.
+func (c *Counter) Add(n int) { c.N += n }
+
+// String returns the count.
+func (c Counter) String() string { return strconv.Itoa(c.N) }
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"example.com/direct"
+	"example.com/used"
+)
+
+func main() {
+	direct.Run()
+	used.Run()
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"testing"
+
+	"example.com/testonly"
+)
+
+func TestMain(t *testing.T) {
+	testonly.Check(t)
+}
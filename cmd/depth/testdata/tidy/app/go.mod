@@ -0,0 +1,12 @@
+module example.com/app
+
+go 1.17
+
+require (
+	example.com/direct v1.0.0 // indirect
+	example.com/testonly v1.2.0
+	example.com/unused v0.3.0
+	example.com/used v1.0.0
+)
+
+require example.com/transitive v1.1.0 // indirect
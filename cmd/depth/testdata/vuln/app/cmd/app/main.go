@@ -0,0 +1,10 @@
+package main
+
+import (
+	"example.com/app/store"
+	_ "example.com/yaml"
+)
+
+func main() {
+	store.Open()
+}
@@ -0,0 +1,8 @@
+module example.com/app
+
+go 1.17
+
+require (
+	example.com/lib v1.2.0
+	example.com/yaml v0.3.0
+)
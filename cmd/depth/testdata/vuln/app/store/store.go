@@ -0,0 +1,8 @@
+package store
+
+import "example.com/lib/parse"
+
+// Open opens the store.
+func Open() {
+	parse.Parse()
+}
@@ -0,0 +1,5 @@
+// Package lib is lighter than util.
+package lib
+
+// Name is the name of the package.
+const Name = "lib"
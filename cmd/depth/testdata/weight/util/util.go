@@ -0,0 +1,27 @@
+// Package util is heavier than lib.
+package util
+
+// Name is the name of the package.
+const Name = "util"
+
+// Upper returns the upper case of an ASCII string.
+func Upper(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'a' && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return string(b)
+}
+
+// Lower returns the lower case of an ASCII string.
+func Lower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c - 'A' + 'a'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,10 @@
+// Package weight imports a light and a heavy package.
+package weight
+
+import (
+	"github.com/KyleBanks/depth/cmd/depth/testdata/weight/lib"
+	"github.com/KyleBanks/depth/cmd/depth/testdata/weight/util"
+)
+
+// Name is the name of the package.
+const Name = lib.Name + util.Name
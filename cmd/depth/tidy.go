@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+// errUntidy is returned when the requirements of go.mod don't match the modules used.
+var errUntidy = errors.New("go.mod requirements don't match the modules used")
+
+// handleTidyReport resolves the packages provided, which default to "./...", along
+// with their test imports, and compares the modules they reach with the requirements
+// of the nearest go.mod file. The requirements that are unused, only used by tests,
+// or marked as indirect but imported directly are written to Stdout, and an error is
+// returned if there are any.
+func handleTidyReport(t *depth.Tree, args []string) error {
+	if len(args) == 0 {
+		args = []string{"./..."}
+	}
+
+	path, err := findGoMod(t.Dir)
+	if err != nil {
+		fmt.Printf("FATAL: %v\n", err)
+		return err
+	}
+
+	mod, err := depth.ReadGoMod(path)
+	if err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", path, err)
+		return err
+	}
+
+	// Only the test imports of the main module are needed, and the test imports of
+	// other modules may not even be available, such as with -mod=vendor.
+	useModules(t)
+	t.ResolveMainTest = true
+	if err := t.ResolveAll(args...); err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", strings.Join(args, " "), err)
+		return err
+	}
+
+	r := t.Graph.Tidy(mod)
+	if opts.format == formatJSON {
+		writeTidyReportJSON(os.Stdout, r)
+	} else {
+		writeTidyReport(os.Stdout, r)
+	}
+
+	if !r.Empty() {
+		return errUntidy
+	}
+	return nil
+}
+
+// findGoMod returns the path of the go.mod file in the directory provided, or the
+// current directory if empty, or in the nearest of their parents.
+func findGoMod(dir string) (string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, "go.mod")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("go.mod file not found in the current directory or any parent directory")
+		}
+		dir = parent
+	}
+}
+
+// writeTidyReport writes each section of the TidyReport that lists requirements,
+// followed by the number of requirements to tidy.
+func writeTidyReport(w io.Writer, r depth.TidyReport) {
+	sections := []struct {
+		title string
+		reqs  []depth.Requirement
+	}{
+		{"Unused requirements:", r.Unused},
+		{"Requirements only used by tests:", r.TestOnly},
+		{"Indirect requirements imported directly:", r.Direct},
+	}
+
+	for _, s := range sections {
+		if len(s.reqs) == 0 {
+			continue
+		}
+
+		fmt.Fprintln(w, s.title)
+		for _, req := range s.reqs {
+			fmt.Fprintf(w, "%v%v\n", outputClosedPadding, req)
+		}
+	}
+
+	fmt.Fprintf(w, "%d requirements to tidy.\n", len(r.Unused)+len(r.TestOnly)+len(r.Direct))
+}
+
+// writeTidyReportJSON writes the TidyReport as JSON, with empty sections as empty
+// arrays.
+func writeTidyReportJSON(w io.Writer, r depth.TidyReport) {
+	for _, reqs := range []*[]depth.Requirement{&r.Unused, &r.TestOnly, &r.Direct} {
+		if *reqs == nil {
+			*reqs = []depth.Requirement{}
+		}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.Encode(r)
+}
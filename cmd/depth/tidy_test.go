@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Example_handleTidyReport() {
+	opts = options{}
+
+	if err := handleTidyReport(vendorTree("testdata/tidy/app"), nil); err != errUntidy {
+		panic(err)
+	}
+	// Output:
+	// Unused requirements:
+	//   example.com/unused v0.3.0
+	// Requirements only used by tests:
+	//   example.com/testonly v1.2.0
+	// Indirect requirements imported directly:
+	//   example.com/direct v1.0.0
+	// 3 requirements to tidy.
+}
+
+func Example_handleTidyReportJSON() {
+	opts = options{format: formatJSON}
+
+	handleTidyReport(vendorTree("testdata/tidy/app"), []string{"./cmd/..."})
+	// Output:
+	// {
+	//   "unused": [
+	//     {
+	//       "path": "example.com/unused",
+	//       "version": "v0.3.0"
+	//     }
+	//   ],
+	//   "testOnly": [
+	//     {
+	//       "path": "example.com/testonly",
+	//       "version": "v1.2.0"
+	//     }
+	//   ],
+	//   "direct": [
+	//     {
+	//       "path": "example.com/direct",
+	//       "version": "v1.0.0",
+	//       "indirect": true
+	//     }
+	//   ]
+	// }
+}
+
+func Example_handleTidyReportTidy() {
+	opts = options{}
+
+	if err := handleTidyReport(vendorTree("testdata/license/app"), nil); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 0 requirements to tidy.
+}
+
+func Test_findGoMod(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := filepath.Join(dir, "go.mod")
+	for _, d := range []string{dir, filepath.Join(dir, "a", "b")} {
+		if path, err := findGoMod(d); err != nil {
+			t.Fatal(err)
+		} else if path != expected {
+			t.Fatalf("[%v] Unexpected path, expected=%v, got=%v", d, expected, path)
+		}
+	}
+}
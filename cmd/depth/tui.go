@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+const tuiHelp = `Commands:
+  <n>        expand or collapse package n
+  n, p       show the next or previous page of packages
+  e          expand all packages
+  c          collapse all packages
+  /<text>    search for packages containing text, revealing them in the tree
+  r <n>      show the packages that import package n
+  b          go back to the dependency tree
+  t          show or hide test-only packages
+  i          show or hide internal packages
+  h          show this help
+  q          quit
+
+Packages marked ^ were expanded in an earlier row, which shows their children.`
+
+// tuiPageSize is the number of rows shown on each page of the explorer.
+const tuiPageSize = 50
+
+// explorer is an interactive view of a resolved Graph, driven by line-based commands
+// read from its input. Packages are shown as a tree that can be expanded, collapsed,
+// searched and viewed in reverse, from a package to its importers.
+//
+// As with writeGraph, the children of each package are only shown the first time it
+// is expanded, and the rows are split into pages of pageSize.
+type explorer struct {
+	g   *depth.Graph
+	in  *bufio.Scanner
+	out io.Writer
+
+	// reverse is the package whose importers are shown, or empty to show the
+	// dependencies of the roots.
+	reverse string
+
+	expanded     map[string]bool
+	matches      map[string]bool
+	hideTest     bool
+	hideInternal bool
+
+	rows     []explorerRow
+	page     int
+	pageSize int
+
+	// showMatch moves to the page of the first match the next time the rows are
+	// rendered.
+	showMatch bool
+}
+
+// explorerRow is a package shown by the explorer, along with the path to it.
+type explorerRow struct {
+	name  string
+	path  string
+	depth int
+
+	// repeated is true when the children of the package are shown by an earlier row.
+	repeated bool
+}
+
+// newExplorer returns an explorer of the Graph, reading commands from r and writing
+// to w. The roots of the Graph are initially expanded.
+func newExplorer(g *depth.Graph, r io.Reader, w io.Writer) *explorer {
+	e := explorer{
+		g:        g,
+		in:       bufio.NewScanner(r),
+		out:      w,
+		expanded: make(map[string]bool),
+		pageSize: tuiPageSize,
+	}
+
+	for _, n := range g.Roots() {
+		e.expanded[n.Name] = true
+	}
+	return &e
+}
+
+// run renders the explorer and handles commands until the input ends or the user quits.
+func (e *explorer) run() {
+	e.render()
+	for {
+		fmt.Fprint(e.out, "> ")
+		if !e.in.Scan() {
+			fmt.Fprintln(e.out)
+			return
+		}
+
+		cmd := strings.TrimSpace(e.in.Text())
+		switch cmd {
+		case "q":
+			return
+		case "h", "?":
+			fmt.Fprintln(e.out, tuiHelp)
+			continue
+		}
+
+		if err := e.handle(cmd); err != nil {
+			fmt.Fprintln(e.out, err)
+			continue
+		}
+		e.render()
+	}
+}
+
+// handle applies a single command to the explorer.
+func (e *explorer) handle(cmd string) error {
+	switch {
+	case cmd == "":
+		return nil
+	case cmd == "n":
+		if (e.page+1)*e.pageSize >= len(e.rows) {
+			return fmt.Errorf("already on the last page")
+		}
+		e.page++
+	case cmd == "p":
+		if e.page == 0 {
+			return fmt.Errorf("already on the first page")
+		}
+		e.page--
+	case cmd == "e":
+		e.expandAll()
+	case cmd == "c":
+		e.expanded = make(map[string]bool)
+	case cmd == "t":
+		e.hideTest = !e.hideTest
+	case cmd == "i":
+		e.hideInternal = !e.hideInternal
+	case cmd == "b":
+		e.reverse = ""
+		e.expanded = make(map[string]bool)
+		for _, n := range e.g.Roots() {
+			e.expanded[n.Name] = true
+		}
+	case strings.HasPrefix(cmd, "/"):
+		e.search(strings.TrimSpace(cmd[1:]))
+	case strings.HasPrefix(cmd, "r "):
+		row, err := e.row(strings.TrimSpace(cmd[2:]))
+		if err != nil {
+			return err
+		}
+		e.reverse = row.name
+		e.expanded = map[string]bool{row.name: true}
+	default:
+		row, err := e.row(cmd)
+		if err != nil {
+			return err
+		}
+		e.expanded[row.path] = !e.expanded[row.path]
+	}
+
+	return nil
+}
+
+// row returns the visible row with the number provided.
+func (e *explorer) row(s string) (explorerRow, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > len(e.rows) {
+		return explorerRow{}, fmt.Errorf("unknown command or package '%v', enter h for help", s)
+	}
+	return e.rows[n-1], nil
+}
+
+// tops returns the packages shown at the top level of the tree.
+func (e *explorer) tops() []string {
+	if e.reverse != "" {
+		return []string{e.reverse}
+	}
+
+	var names []string
+	for _, n := range e.g.Roots() {
+		names = append(names, n.Name)
+	}
+	return names
+}
+
+// children returns the visible packages below a package in the tree, which are its
+// imports, or its importers in reverse.
+func (e *explorer) children(name string) []string {
+	var nodes []*depth.Node
+	if e.reverse != "" {
+		nodes = e.g.ImportersOf(name)
+	} else {
+		for _, edge := range e.g.EdgesFrom(name) {
+			if e.hideTest && edge.Test {
+				continue
+			}
+			nodes = append(nodes, e.g.Node(edge.To))
+		}
+	}
+
+	var names []string
+	for _, n := range nodes {
+		if e.isVisible(n) {
+			names = append(names, n.Name)
+		}
+	}
+	return names
+}
+
+// isVisible returns true if the Node is not hidden by the test or internal toggles.
+func (e *explorer) isVisible(n *depth.Node) bool {
+	return !(e.hideTest && n.Test) && !(e.hideInternal && n.Internal)
+}
+
+// expandAll expands the first row of every package in the tree, in the same order
+// as addRows.
+func (e *explorer) expandAll() {
+	seen := make(map[string]bool)
+
+	var walk func(name, path string)
+	walk = func(name, path string) {
+		e.expanded[path] = true
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+
+		for _, c := range e.children(name) {
+			walk(c, path+">"+c)
+		}
+	}
+
+	for _, name := range e.tops() {
+		walk(name, name)
+	}
+}
+
+// search marks the packages containing the text and expands the shortest path from
+// the top of the tree to each of them.
+func (e *explorer) search(text string) {
+	e.matches = make(map[string]bool)
+	if text == "" {
+		return
+	}
+
+	for _, n := range e.g.Nodes() {
+		if !strings.Contains(n.Name, text) || !e.isVisible(n) {
+			continue
+		}
+		e.matches[n.Name] = true
+
+		for _, top := range e.tops() {
+			var p []string
+			if e.reverse != "" {
+				p = reversePath(e.g.ShortestPath(n.Name, top))
+			} else {
+				p = e.g.ShortestPath(top, n.Name)
+			}
+
+			for i := 1; i < len(p); i++ {
+				e.expanded[strings.Join(p[:i], ">")] = true
+			}
+		}
+	}
+
+	if len(e.matches) == 0 {
+		fmt.Fprintf(e.out, "No packages match '%v'.\n", text)
+	}
+	e.showMatch = len(e.matches) > 0
+}
+
+// render writes the visible rows of the current page, numbered for use in commands,
+// followed by a summary of the packages shown.
+func (e *explorer) render() {
+	e.rows = nil
+	seen := make(map[string]bool)
+	for _, name := range e.tops() {
+		e.addRows(name, name, 0, seen)
+	}
+
+	e.movePage()
+	start, end := e.page*e.pageSize, (e.page+1)*e.pageSize
+	if end > len(e.rows) {
+		end = len(e.rows)
+	}
+
+	if e.reverse != "" {
+		fmt.Fprintf(e.out, "Importers of %v:\n", e.reverse)
+	}
+
+	width := len(strconv.Itoa(end))
+	for i, r := range e.rows[start:end] {
+		marker := " "
+		if r.repeated {
+			marker = "^"
+		} else if len(e.children(r.name)) > 0 {
+			marker = "+"
+			if e.expanded[r.path] {
+				marker = "-"
+			}
+		}
+
+		match := ""
+		if e.matches[r.name] {
+			match = " *"
+		}
+
+		fmt.Fprintf(e.out, "%*d %v%v %v%v\n", width, start+i+1, strings.Repeat(outputClosedPadding, r.depth), marker, e.g.Node(r.name).Pkg.String(), match)
+	}
+
+	e.writeSummary()
+}
+
+// movePage keeps the current page within the rows, or moves to the page of the first
+// match after a search.
+func (e *explorer) movePage() {
+	if e.showMatch {
+		e.showMatch = false
+		for i, r := range e.rows {
+			if e.matches[r.name] {
+				e.page = i / e.pageSize
+				return
+			}
+		}
+	}
+
+	if last := (len(e.rows) - 1) / e.pageSize; e.page > last {
+		e.page = last
+	}
+}
+
+// addRows adds the row of a package, and of its children if it is expanded. The
+// children of a package are only added the first time it is expanded, and later
+// expanded rows of the package are marked as repeated.
+func (e *explorer) addRows(name, path string, depth int, seen map[string]bool) {
+	row := explorerRow{name: name, path: path, depth: depth}
+	if !e.expanded[path] {
+		e.rows = append(e.rows, row)
+		return
+	}
+
+	row.repeated = seen[name]
+	e.rows = append(e.rows, row)
+	if row.repeated {
+		return
+	}
+
+	seen[name] = true
+	for _, c := range e.children(name) {
+		e.addRows(c, path+">"+c, depth+1, seen)
+	}
+}
+
+// writeSummary writes the number of visible packages, excluding the roots.
+func (e *explorer) writeSummary() {
+	var sum depth.Summary
+	roots := make(map[string]bool)
+	for _, n := range e.g.Roots() {
+		roots[n.Name] = true
+	}
+
+	for _, n := range e.g.Nodes() {
+		if roots[n.Name] || !e.isVisible(n) {
+			continue
+		}
+
+		if n.Internal {
+			sum.Internal++
+		} else {
+			sum.External++
+		}
+		if n.Test {
+			sum.Testing++
+		}
+	}
+
+	var page string
+	if pages := (len(e.rows)-1)/e.pageSize + 1; pages > 1 {
+		page = fmt.Sprintf(", page %d of %d", e.page+1, pages)
+	}
+
+	fmt.Fprintf(e.out, "-- %d rows shown of %d dependencies (%d internal, %d external, %d testing)%v --\n",
+		len(e.rows),
+		sum.Total(),
+		sum.Internal,
+		sum.External,
+		sum.Testing,
+		page)
+}
+
+// reversePath returns a reversed copy of the path.
+func reversePath(p []string) []string {
+	r := make([]string, len(p))
+	for i, name := range p {
+		r[len(p)-1-i] = name
+	}
+	return r
+}
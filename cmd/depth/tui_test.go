@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+func Example_explorer() {
+	t := depth.Tree{ResolveTest: true}
+	if err := t.Resolve("github.com/KyleBanks/depth/cmd/depth/testdata/dot"); err != nil {
+		panic(err)
+	}
+
+	in := strings.NewReader("t\ni\n1\n9\nq\n")
+	newExplorer(t.Graph, in, os.Stdout).run()
+	// Output:
+	// 1 - github.com/KyleBanks/depth/cmd/depth/testdata/dot
+	// 2     strings
+	// 3     testing
+	// 4     github.com/KyleBanks/depth/cmd/depth/testdata/dot/lib
+	// 5     github.com/KyleBanks/depth/cmd/depth/testdata/notreal (unresolved: missing)
+	// -- 5 rows shown of 4 dependencies (2 internal, 2 external, 1 testing) --
+	// > 1 - github.com/KyleBanks/depth/cmd/depth/testdata/dot
+	// 2     strings
+	// 3     github.com/KyleBanks/depth/cmd/depth/testdata/dot/lib
+	// 4     github.com/KyleBanks/depth/cmd/depth/testdata/notreal (unresolved: missing)
+	// -- 4 rows shown of 3 dependencies (1 internal, 2 external, 0 testing) --
+	// > 1 - github.com/KyleBanks/depth/cmd/depth/testdata/dot
+	// 2     github.com/KyleBanks/depth/cmd/depth/testdata/dot/lib
+	// 3     github.com/KyleBanks/depth/cmd/depth/testdata/notreal (unresolved: missing)
+	// -- 3 rows shown of 2 dependencies (0 internal, 2 external, 0 testing) --
+	// > 1 + github.com/KyleBanks/depth/cmd/depth/testdata/dot
+	// -- 1 rows shown of 2 dependencies (0 internal, 2 external, 0 testing) --
+	// > unknown command or package '9', enter h for help
+	// >
+}
+
+func Example_explorerSearch() {
+	var t depth.Tree
+	if err := t.ResolveAll("./testdata/rdeps/..."); err != nil {
+		panic(err)
+	}
+
+	in := strings.NewReader("c\n/rdeps/c\n/notreal\nr 3\n/rdeps/a\nb\n")
+	newExplorer(t.Graph, in, os.Stdout).run()
+	// Output:
+	// 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 2   + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 3 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 4     github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// 5   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing) --
+	// > 1 + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 2 + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 3   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// -- 3 rows shown of 0 dependencies (0 internal, 0 external, 0 testing) --
+	// > 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 2   - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 3       github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c *
+	// 4 ^ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 5   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c *
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing) --
+	// > No packages match 'notreal'.
+	// 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 2   - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 3       github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// 4 ^ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 5   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing) --
+	// > Importers of github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c:
+	// 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// 2   + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// -- 2 rows shown of 0 dependencies (0 internal, 0 external, 0 testing) --
+	// > Importers of github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c:
+	// 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// 2   - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 3       github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a *
+	// -- 3 rows shown of 0 dependencies (0 internal, 0 external, 0 testing) --
+	// > 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a *
+	// 2   + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 3 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 4     github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// 5   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing) --
+	// >
+}
+
+func Example_explorerExpandAll() {
+	var t depth.Tree
+	if err := t.Resolve("github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a"); err != nil {
+		panic(err)
+	}
+
+	in := strings.NewReader("c\ne\nh\nq\n")
+	newExplorer(t.Graph, in, os.Stdout).run()
+	// Output:
+	// 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 2   + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// -- 2 rows shown of 2 dependencies (0 internal, 2 external, 0 testing) --
+	// > 1 + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// -- 1 rows shown of 2 dependencies (0 internal, 2 external, 0 testing) --
+	// > 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 2   - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 3       github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// -- 3 rows shown of 2 dependencies (0 internal, 2 external, 0 testing) --
+	// > Commands:
+	//   <n>        expand or collapse package n
+	//   n, p       show the next or previous page of packages
+	//   e          expand all packages
+	//   c          collapse all packages
+	//   /<text>    search for packages containing text, revealing them in the tree
+	//   r <n>      show the packages that import package n
+	//   b          go back to the dependency tree
+	//   t          show or hide test-only packages
+	//   i          show or hide internal packages
+	//   h          show this help
+	//   q          quit
+	//
+	// Packages marked ^ were expanded in an earlier row, which shows their children.
+	// >
+}
+
+func Example_explorerPages() {
+	var t depth.Tree
+	if err := t.ResolveAll("./testdata/rdeps/..."); err != nil {
+		panic(err)
+	}
+
+	e := newExplorer(t.Graph, strings.NewReader("n\nn\nn\np\np\np\n/rdeps/c\nq\n"), os.Stdout)
+	e.pageSize = 2
+	e.run()
+	// Output:
+	// 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 2   + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing), page 1 of 3 --
+	// > 3 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 4     github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing), page 2 of 3 --
+	// > 5   github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing), page 3 of 3 --
+	// > already on the last page
+	// > 3 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// 4     github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing), page 2 of 3 --
+	// > 1 - github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a
+	// 2   + github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing), page 1 of 3 --
+	// > already on the first page
+	// > 3       github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c *
+	// 4 ^ github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b
+	// -- 5 rows shown of 0 dependencies (0 internal, 0 external, 0 testing), page 2 of 3 --
+	// >
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+// errVulnUsage is returned when the vuln command isn't provided a database.
+var errVulnUsage = errors.New("usage: depth vuln -db <dir> [flags] [patterns]")
+
+// errVulnerable is returned when vulnerable packages are imported.
+var errVulnerable = errors.New("vulnerable packages imported")
+
+// vulnJSON is the JSON representation of a depth.VulnFinding.
+type vulnJSON struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Summary  string   `json:"summary,omitempty"`
+	Module   string   `json:"module"`
+	Version  string   `json:"version"`
+	Fixed    string   `json:"fixed,omitempty"`
+	Imported bool     `json:"imported"`
+	Package  string   `json:"package,omitempty"`
+	Symbols  []string `json:"symbols,omitempty"`
+	Path     []string `json:"path,omitempty"`
+}
+
+// handleVuln resolves the packages provided, which default to "./...", and checks
+// the module versions they use against the OSV vulnerability database in the -db
+// directory. Each vulnerability is written to Stdout along with the imports reaching
+// the vulnerable packages, and an error is returned if any of them are imported.
+func handleVuln(t *depth.Tree, args []string) error {
+	if opts.vulnDB == "" {
+		fmt.Println(errVulnUsage)
+		return errVulnUsage
+	}
+
+	db, err := depth.ReadVulnDB(opts.vulnDB)
+	if err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", opts.vulnDB, err)
+		return err
+	}
+
+	if len(args) == 0 {
+		args = []string{"./..."}
+	}
+
+	// Vulnerable stdlib packages are often only imported by other stdlib packages.
+	useModules(t)
+	t.ResolveInternal = true
+	if err := t.ResolveAll(args...); err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", strings.Join(args, " "), err)
+		return err
+	}
+
+	findings := db.Check(t)
+	if opts.format == formatJSON {
+		writeVulnsJSON(os.Stdout, findings)
+	} else {
+		writeVulns(os.Stdout, t.Graph, findings)
+	}
+
+	for _, f := range findings {
+		if f.Imported() {
+			return errVulnerable
+		}
+	}
+	return nil
+}
+
+// writeVulns writes each vulnerability affecting a module version, followed by the
+// shortest chain of imports from a root to each vulnerable package, in the same form
+// as -explain with -files.
+func writeVulns(w io.Writer, g *depth.Graph, findings []depth.VulnFinding) {
+	var imported, unused int
+	for i, f := range findings {
+		if i == 0 || f.Vuln != findings[i-1].Vuln || f.Module != findings[i-1].Module {
+			writeVulnHeader(w, f)
+			if f.Imported() {
+				imported++
+			} else {
+				unused++
+			}
+		}
+
+		if !f.Imported() {
+			fmt.Fprintf(w, "%vThe vulnerable packages are not imported.\n", outputClosedPadding)
+			continue
+		}
+
+		symbols := ""
+		if len(f.Symbols) > 0 {
+			symbols = " [" + strings.Join(f.Symbols, ", ") + "]"
+		}
+		fmt.Fprintf(w, "%v%v%v\n", outputClosedPadding, strings.Join(explainFiles(g, f.Path), " -> "), symbols)
+	}
+
+	fmt.Fprintf(w, "%d vulnerabilities imported, %d in modules used without importing the vulnerable packages.\n", imported, unused)
+}
+
+// writeVulnHeader writes the ID, aliases and summary of the vulnerability of a finding,
+// and the affected module version.
+func writeVulnHeader(w io.Writer, f depth.VulnFinding) {
+	id := f.Vuln.ID
+	if len(f.Vuln.Aliases) > 0 {
+		id += " (" + strings.Join(f.Vuln.Aliases, ", ") + ")"
+	}
+	if f.Vuln.Summary != "" {
+		id += ": " + f.Vuln.Summary
+	}
+	fmt.Fprintln(w, id)
+
+	fixed := "no fix available"
+	if f.Fixed != "" {
+		fixed = "fixed in " + f.Fixed
+	}
+	fmt.Fprintf(w, "%v%v %v, %v\n", outputClosedPadding, f.Module, f.Version, fixed)
+}
+
+// writeVulnsJSON writes the findings as a JSON array.
+func writeVulnsJSON(w io.Writer, findings []depth.VulnFinding) {
+	out := []vulnJSON{}
+	for _, f := range findings {
+		out = append(out, vulnJSON{
+			ID:       f.Vuln.ID,
+			Aliases:  f.Vuln.Aliases,
+			Summary:  f.Vuln.Summary,
+			Module:   f.Module,
+			Version:  f.Version,
+			Fixed:    f.Fixed,
+			Imported: f.Imported(),
+			Package:  f.Package,
+			Symbols:  f.Symbols,
+			Path:     f.Path,
+		})
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	e.Encode(out)
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func Example_handleVuln() {
+	opts = options{vulnDB: "testdata/vuln/db"}
+
+	handleVuln(vendorTree("testdata/vuln/app"), nil)
+	// Output:
+	// GO-2024-0001 (CVE-2024-0001): Stack exhaustion in example.com/lib/parse
+	//   example.com/lib v1.2.0, fixed in v1.2.1
+	//   example.com/app/store -> example.com/lib/parse (store.go:3) [Parse]
+	// GO-2024-0002: Code injection in example.com/lib/template
+	//   example.com/lib v1.2.0, no fix available
+	//   The vulnerable packages are not imported.
+	// 1 vulnerabilities imported, 1 in modules used without importing the vulnerable packages.
+}
+
+func Example_handleVulnJSON() {
+	opts = options{vulnDB: "testdata/vuln/db", format: formatJSON}
+
+	handleVuln(vendorTree("testdata/vuln/app"), []string{"./cmd/..."})
+	// Output:
+	// [
+	//   {
+	//     "id": "GO-2024-0001",
+	//     "aliases": [
+	//       "CVE-2024-0001"
+	//     ],
+	//     "summary": "Stack exhaustion in example.com/lib/parse",
+	//     "module": "example.com/lib",
+	//     "version": "v1.2.0",
+	//     "fixed": "v1.2.1",
+	//     "imported": true,
+	//     "package": "example.com/lib/parse",
+	//     "symbols": [
+	//       "Parse"
+	//     ],
+	//     "path": [
+	//       "example.com/app/cmd/app",
+	//       "example.com/app/store",
+	//       "example.com/lib/parse"
+	//     ]
+	//   },
+	//   {
+	//     "id": "GO-2024-0002",
+	//     "summary": "Code injection in example.com/lib/template",
+	//     "module": "example.com/lib",
+	//     "version": "v1.2.0",
+	//     "imported": false
+	//   }
+	// ]
+}
+
+func Example_handleVulnUsage() {
+	opts = options{}
+
+	handleVuln(vendorTree("testdata/vuln/app"), nil)
+	// Output:
+	// usage: depth vuln -db <dir> [flags] [patterns]
+}
+
+func Test_handleVulnExitCode(t *testing.T) {
+	opts = options{vulnDB: "testdata/vuln/db"}
+	defer func() { opts = options{} }()
+
+	if err := handleVuln(vendorTree("testdata/vuln/app"), []string{"./cmd/..."}); err != errVulnerable {
+		t.Fatalf("Unexpected error, expected=%v, got=%v", errVulnerable, err)
+	}
+}
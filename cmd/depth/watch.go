@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/KyleBanks/depth"
+)
+
+// handleWatch resolves the packages provided, which default to "./...", and writes
+// their Tree. It then watches their source files, and writes the changes to their
+// dependencies each time they are re-resolved, until the process is stopped.
+func handleWatch(t *depth.Tree, pkgs []string, o options) error {
+	if len(pkgs) == 0 {
+		pkgs = []string{"./..."}
+	}
+
+	w := depth.Watcher{Tree: t, Patterns: pkgs, Interval: o.watchInterval}
+	err := w.Watch(nil, func(e depth.WatchEvent) {
+		writeWatchEvent(os.Stdout, e, o)
+	})
+	if err != nil {
+		fmt.Printf("'%v': FATAL: %v\n", strings.Join(pkgs, " "), err)
+	}
+	return err
+}
+
+// writeWatchEvent writes the Tree of the first resolution of a Watcher, and the
+// changes to the dependencies of each later resolution.
+func writeWatchEvent(w io.Writer, e depth.WatchEvent, o options) {
+	if e.Prev == nil {
+		writeTree(w, e.Tree, o)
+		fmt.Fprintln(w, "Watching for changes...")
+		return
+	}
+
+	fmt.Fprintf(w, "\nChanged: %v\n", strings.Join(e.Changed, ", "))
+	if e.Err != nil {
+		fmt.Fprintf(w, "FATAL: %v\n", e.Err)
+		return
+	}
+
+	d := depth.Diff(e.Prev, e.Tree)
+	if d.Empty() {
+		fmt.Fprintln(w, "No dependency changes.")
+		return
+	}
+
+	for _, edge := range d.AddedEdges {
+		fmt.Fprintf(w, "+ import %v -> %v%v\n", edge.From, edge.To, positionsSuffix(edge.Files))
+	}
+	for _, edge := range d.RemovedEdges {
+		fmt.Fprintf(w, "- import %v -> %v\n", edge.From, edge.To)
+	}
+	writeDiff(w, e.Prev, e.Tree, d)
+}
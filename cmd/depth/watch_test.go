@@ -0,0 +1,87 @@
+package main
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyleBanks/depth"
+)
+
+func Test_parseWatch(t *testing.T) {
+	parse([]string{"-watch", "-watch-interval=250ms"})
+	if !opts.watch {
+		t.Fatal("Expected watch to be set")
+	} else if opts.watchInterval != 250*time.Millisecond {
+		t.Fatalf("Unexpected watchInterval, expected=%v, got=%v", 250*time.Millisecond, opts.watchInterval)
+	}
+
+	parse([]string{})
+	if opts.watch {
+		t.Fatal("Unexpected watch")
+	} else if opts.watchInterval != depth.DefaultWatchInterval {
+		t.Fatalf("Unexpected watchInterval, expected=%v, got=%v", depth.DefaultWatchInterval, opts.watchInterval)
+	}
+}
+
+func Example_writeWatchEvent() {
+	dir, err := os.MkdirTemp("", "depth")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, contents string) {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			panic(err)
+		}
+	}
+	write("go.mod", "module example.com/w\n")
+	write("a/a.go", "package a\n\nimport _ \"example.com/w/b\"\n")
+	write("b/b.go", "package b\n")
+	write("c/c.go", "package c\n\nimport _ \"strings\"\n")
+
+	ctx := build.Default
+	ctx.Dir = dir
+	w := depth.Watcher{Tree: &depth.Tree{Dir: dir, Context: &ctx}, Patterns: []string{"./a"}}
+
+	prev, err := w.Resolve()
+	if err != nil {
+		panic(err)
+	}
+	writeWatchEvent(os.Stdout, depth.WatchEvent{Tree: prev}, options{})
+
+	write("a/a.go", "package a\n\nimport (\n\t_ \"example.com/w/c\"\n)\n")
+	changed := w.Changed()
+	next, err := w.Resolve()
+	writeWatchEvent(os.Stdout, depth.WatchEvent{Prev: prev, Tree: next, Changed: changed, Err: err}, options{})
+
+	write("b/b.go", "package b // no longer imported\n")
+	write("c/c.go", "package c\n\nimport (\n\t_ \"example.com/w/b\"\n\t_ \"strings\"\n)\n")
+	changed = w.Changed()
+	last, err := w.Resolve()
+	writeWatchEvent(os.Stdout, depth.WatchEvent{Prev: next, Tree: last, Changed: changed, Err: err}, options{})
+	// Output:
+	// example.com/w/a
+	//   └ example.com/w/b
+	// 1 dependencies (0 internal, 1 external, 0 testing).
+	// Watching for changes...
+	//
+	// Changed: example.com/w/a
+	// + import example.com/w/a -> example.com/w/c (a.go:4)
+	// + import example.com/w/c -> strings (c.go:3)
+	// - import example.com/w/a -> example.com/w/b
+	// + strings (example.com/w/a -> example.com/w/c -> strings)
+	// + example.com/w/c (example.com/w/a -> example.com/w/c)
+	// - example.com/w/b (example.com/w/a -> example.com/w/b)
+	// 1 -> 2 (+1) dependencies (0 -> 1 (+1) internal, 1 external, 0 testing).
+	//
+	// Changed: example.com/w/c
+	// + import example.com/w/c -> example.com/w/b (c.go:4)
+	// + example.com/w/b (example.com/w/a -> example.com/w/c -> example.com/w/b)
+	// 2 -> 3 (+1) dependencies (1 internal, 1 -> 2 (+1) external, 0 testing).
+}
@@ -0,0 +1,183 @@
+package depth
+
+import (
+	"sort"
+)
+
+// Cycle is a group of packages that import each other, directly or transitively.
+type Cycle struct {
+	// Packages contains every package within the cycle, in the same order as
+	// byInternalAndName.
+	Packages []string
+
+	// Path is an ordered chain of imports through the cycle, starting and ending
+	// with the same package.
+	Path []string
+
+	// Test is true when the cycle only exists because of test imports.
+	Test bool
+}
+
+// Cycles returns the import cycles within the resolved Tree.
+func (t *Tree) Cycles() []Cycle {
+	if t.Graph == nil {
+		return nil
+	}
+
+	return t.Graph.Cycles()
+}
+
+// Cycles returns each group of packages in the Graph that import each other, found
+// as the strongly connected components of the Graph.
+//
+// Test imports are included, so cycles created by external test packages are
+// reported and flagged as Test.
+func (g *Graph) Cycles() []Cycle {
+	// Components that remain intact without test imports aren't caused by them.
+	withoutTest := make(map[string]int)
+	for _, members := range g.components(false) {
+		for _, m := range members {
+			withoutTest[m] = len(members)
+		}
+	}
+
+	var cycles []Cycle
+	for _, members := range g.components(true) {
+		if len(members) < 2 {
+			continue
+		}
+
+		test := withoutTest[members[0]] != len(members)
+		cycles = append(cycles, Cycle{
+			Packages: members,
+			Path:     g.shortestCycle(members, test),
+			Test:     test,
+		})
+	}
+
+	return cycles
+}
+
+// components returns the strongly connected components of the Graph using Tarjan's
+// algorithm, optionally following test imports.
+//
+// Components are returned in the order of their first Node, and the members of each
+// component are in the same order as the Nodes of the Graph.
+func (g *Graph) components(withTest bool) [][]string {
+	nodes := g.Nodes()
+	order := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		order[n.Name] = i
+	}
+
+	var (
+		index   = make(map[string]int)
+		lowlink = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   []string
+		found   [][]string
+	)
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		lowlink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, e := range g.imports[name] {
+			if e.Test && !withTest {
+				continue
+			}
+
+			if _, ok := index[e.To]; !ok {
+				connect(e.To)
+				if lowlink[e.To] < lowlink[name] {
+					lowlink[name] = lowlink[e.To]
+				}
+			} else if onStack[e.To] && index[e.To] < lowlink[name] {
+				lowlink[name] = index[e.To]
+			}
+		}
+
+		if lowlink[name] != index[name] {
+			return
+		}
+
+		var members []string
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+			members = append(members, n)
+			if n == name {
+				break
+			}
+		}
+		found = append(found, members)
+	}
+
+	for _, n := range nodes {
+		if _, ok := index[n.Name]; !ok {
+			connect(n.Name)
+		}
+	}
+
+	for _, members := range found {
+		sort.Slice(members, func(i, j int) bool {
+			return order[members[i]] < order[members[j]]
+		})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return order[found[i][0]] < order[found[j][0]]
+	})
+	return found
+}
+
+// shortestCycle returns the shortest chain of imports from the first member of a
+// component back to itself, staying within the component and optionally following
+// test imports. Nil is returned if no such chain exists.
+func (g *Graph) shortestCycle(members []string, withTest bool) []string {
+	within := make(map[string]struct{}, len(members))
+	for _, m := range members {
+		within[m] = struct{}{}
+	}
+
+	start := members[0]
+	parents := make(map[string]string)
+	var queue []string
+	for _, e := range g.imports[start] {
+		if _, ok := within[e.To]; ok && (withTest || !e.Test) {
+			if _, seen := parents[e.To]; !seen {
+				parents[e.To] = start
+				queue = append(queue, e.To)
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if name == start {
+			path := []string{start}
+			for n := parents[start]; n != start; n = parents[n] {
+				path = append([]string{n}, path...)
+			}
+			return append([]string{start}, path...)
+		}
+
+		for _, e := range g.imports[name] {
+			if _, ok := within[e.To]; !ok || (e.Test && !withTest) {
+				continue
+			}
+			if _, seen := parents[e.To]; seen {
+				continue
+			}
+			parents[e.To] = name
+			queue = append(queue, e.To)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package depth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTree_Cycles(t *testing.T) {
+	tr := Tree{
+		ResolveTest: true,
+		Importer: mockGraphImporter(map[string][]string{
+			"a":      {"b", "e"},
+			"b":      {"c"},
+			"c":      {"d"},
+			"d":      {"b"},
+			"e":      {"f"},
+			"f":      {},
+			"f_test": {"g"},
+			"g":      {"e"},
+		}),
+	}
+	if err := tr.Resolve("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Cycle{
+		{Packages: []string{"b", "c", "d"}, Path: []string{"b", "c", "d", "b"}},
+		{Packages: []string{"e", "f", "g"}, Path: []string{"e", "f", "g", "e"}, Test: true},
+	}
+	if cycles := tr.Cycles(); !reflect.DeepEqual(cycles, expected) {
+		t.Fatalf("Unexpected Cycles, expected=%+v, got=%+v", expected, cycles)
+	}
+
+	// Without test imports, the second cycle is never resolved.
+	tr.ResolveTest = false
+	if err := tr.Resolve("a"); err != nil {
+		t.Fatal(err)
+	}
+	if cycles := tr.Cycles(); !reflect.DeepEqual(cycles, expected[:1]) {
+		t.Fatalf("Unexpected Cycles, expected=%+v, got=%+v", expected[:1], cycles)
+	}
+
+	var empty Tree
+	if cycles := empty.Cycles(); cycles != nil {
+		t.Fatalf("Unexpected Cycles for unresolved Tree, expected=nil, got=%v", cycles)
+	}
+}
+
+func TestGraph_shortestCycle(t *testing.T) {
+	g := pathsGraph(t)
+
+	if out := g.shortestCycle([]string{"a", "b", "c", "d"}, false); !reflect.DeepEqual(out, []string{"a", "d", "a"}) {
+		t.Fatalf("Unexpected shortestCycle, got=%v", out)
+	}
+	if out := g.shortestCycle([]string{"e"}, false); out != nil {
+		t.Fatalf("Unexpected shortestCycle, expected=nil, got=%v", out)
+	}
+}
@@ -22,14 +22,18 @@
 //  	ResolveInternal: true,
 //   	ResolveTest: true,
 //   	MaxDepth: 10,
+//   	Workers: runtime.NumCPU(),
 // 	}
 // 	err := t.Resolve("strings")
 package depth
 
 import (
+	"encoding/json"
 	"errors"
 	"go/build"
+	"io"
 	"os"
+	"path/filepath"
 )
 
 // ErrRootPkgNotResolved is returned when the root Pkg of the Tree cannot be resolved,
@@ -46,47 +50,183 @@ type Importer interface {
 type Tree struct {
 	Root *Pkg
 
+	// Roots contains each package the Tree was resolved from. When resolved by
+	// Resolve, it only contains the Root, which is always the first of the Roots.
+	Roots []*Pkg
+
+	// Graph is a deduplicated view of the resolved Root and its dependencies.
+	Graph *Graph
+
 	ResolveInternal bool
 	ResolveTest     bool
 	MaxDepth        int
 
+	// ResolveMainTest resolves the test imports of the roots and of the packages of
+	// the main module, as reported by a ModuleImporter, but not those of the packages
+	// of other modules. It has no effect when ResolveTest is set.
+	ResolveMainTest bool
+
+	// ResolveSymbols type-checks each resolved package, and records the exported
+	// identifiers it references from each of its dependencies in their Symbols.
+	// This requires parsing the source of every imported package, including the
+	// standard library, so it is considerably slower.
+	ResolveSymbols bool
+
+	// Workers sets the number of packages imported concurrently. Values below two
+	// resolve packages sequentially. The resolved Tree is the same either way.
+	Workers int
+
+	// Importer is used to import each package, and must be safe for concurrent use
+	// when Workers is set.
 	Importer Importer
 
+	// Context is the build context used to import packages when no Importer is set,
+	// and can be used to resolve the Tree with other build tags, GOOS, GOARCH or cgo
+	// settings than the host. If nil, build.Default is used.
+	Context *build.Context
+
+	// KeepCgo keeps the CgoEnabled setting of the Context for every platform resolved
+	// by ResolvePlatforms. Otherwise, as with the go command, cgo is disabled for the
+	// platforms other than the host.
+	KeepCgo bool
+
+	// Dir is the directory that package names are resolved relative to. If empty,
+	// the current working directory is used.
+	Dir string
+
 	importCache map[string]struct{}
+
+	// rootSet contains the Roots created by the last resolve.
+	rootSet map[*Pkg]struct{}
 }
 
 // Resolve recursively finds all dependencies for the root Pkg name provided,
 // and the packages it depends on.
 func (t *Tree) Resolve(name string) error {
-	pwd, err := os.Getwd()
+	return t.resolve([]string{name})
+}
+
+// ResolveAll expands the package patterns provided, as with Expand, and resolves
+// every matching package as a root of the Tree. Dependencies shared by the roots
+// are resolved once, and the Graph contains the roots and all of their dependencies.
+//
+// An error is only returned if none of the roots can be resolved. Other unresolved
+// roots are reported by Err.
+func (t *Tree) ResolveAll(patterns ...string) error {
+	names, err := t.Expand(patterns...)
 	if err != nil {
 		return err
 	}
+	if len(names) == 0 {
+		return ErrNoPackages
+	}
+
+	return t.resolve(names)
+}
 
-	t.Root = &Pkg{
-		Name:   name,
-		Tree:   t,
-		SrcDir: pwd,
-		Test:   false,
+// resolve resolves each of the root package names provided.
+func (t *Tree) resolve(names []string) error {
+	pwd, err := t.srcDir()
+	if err != nil {
+		return err
 	}
 
 	// Reset the import cache each time to ensure a reused Tree doesn't
 	// reuse the same cache.
 	t.importCache = nil
+	t.Roots = make([]*Pkg, 0, len(names))
+	t.rootSet = make(map[*Pkg]struct{}, len(names))
 
-	// Allow custom importers, but use build.Default if none is provided.
-	if t.Importer == nil {
-		t.Importer = &build.Default
+	i := t.importer()
+	if t.Workers > 1 {
+		f := newPrefetcher(t, i, t.Workers)
+		defer f.wait()
+
+		for _, name := range names {
+			f.fetch(name, pwd, 0)
+		}
+		i = f
+	}
+
+	for _, name := range names {
+		root := &Pkg{
+			Name:   name,
+			Tree:   t,
+			SrcDir: pwd,
+			Test:   false,
+		}
+		t.Roots = append(t.Roots, root)
+		t.rootSet[root] = struct{}{}
 	}
 
-	t.Root.Resolve(t.Importer)
-	if !t.Root.Resolved {
+	resolved := false
+	for _, root := range t.Roots {
+		root.Resolve(i)
+		resolved = resolved || root.Resolved
+	}
+
+	t.Root = t.Roots[0]
+	if t.ResolveSymbols {
+		t.resolveSymbols()
+	}
+	t.Graph = newGraph(t.Roots...)
+	if !resolved {
 		return ErrRootPkgNotResolved
 	}
 
 	return nil
 }
 
+// ReadTree reads a Tree previously written as the JSON encoding of its Root Pkg, such
+// as the output of `depth -json`.
+func ReadTree(r io.Reader) (*Tree, error) {
+	var root Pkg
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		return nil, err
+	}
+
+	t := &Tree{Root: &root, Roots: []*Pkg{&root}}
+	t.link(t.Root)
+	t.Graph = newGraph(t.Root)
+	return t, nil
+}
+
+// link recursively sets the Tree and Parent of the dependencies of a decoded Pkg, and
+// the Name of its ResolveError.
+func (t *Tree) link(p *Pkg) {
+	p.Tree = t
+	if p.Err != nil {
+		p.Err.Name = p.Name
+	}
+	for i := range p.Deps {
+		p.Deps[i].Parent = p
+		t.link(&p.Deps[i])
+	}
+}
+
+// srcDir returns the directory that package names are resolved relative to.
+func (t *Tree) srcDir() (string, error) {
+	if t.Dir != "" {
+		return filepath.Abs(t.Dir)
+	}
+
+	return os.Getwd()
+}
+
+// importer returns the Importer of the Tree.
+//
+// Custom importers are allowed, but the Context of the Tree, or build.Default, is
+// used if none is provided.
+func (t *Tree) importer() Importer {
+	if t.Importer == nil && t.Context != nil {
+		t.Importer = t.Context
+	} else if t.Importer == nil {
+		t.Importer = &build.Default
+	}
+
+	return t.Importer
+}
+
 // shouldResolveInternal determines if internal packages should be further resolved beyond the
 // current parent.
 //
@@ -99,7 +239,32 @@ func (t *Tree) shouldResolveInternal(parent *Pkg) bool {
 		return true
 	}
 
-	return parent == t.Root
+	return t.isRoot(parent)
+}
+
+// isRoot returns true if the Pkg provided is one of the Roots of the Tree.
+func (t *Tree) isRoot(p *Pkg) bool {
+	if t.rootSet != nil {
+		_, ok := t.rootSet[p]
+		return ok
+	}
+
+	// Trees that weren't resolved, such as those read by ReadTree, have no rootSet.
+	for _, r := range t.roots() {
+		if p == r {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldResolveTest determines if the test imports of the Pkg should be resolved.
+func (t *Tree) shouldResolveTest(p *Pkg) bool {
+	if t.ResolveTest {
+		return true
+	}
+
+	return t.ResolveMainTest && (t.isRoot(p) || (p.Module != nil && p.Module.Main))
 }
 
 // isAtMaxDepth returns true when the depth of the Pkg provided is at or beyond the maximum
@@ -114,6 +279,15 @@ func (t *Tree) isAtMaxDepth(p *Pkg) bool {
 	return p.depth() >= t.MaxDepth
 }
 
+// roots returns the Roots of the Tree, or only the Root if the Roots are not set,
+// such as when the Tree was constructed manually.
+func (t *Tree) roots() []*Pkg {
+	if len(t.Roots) == 0 && t.Root != nil {
+		return []*Pkg{t.Root}
+	}
+	return t.Roots
+}
+
 // hasSeenImport returns true if the import name provided has already been seen within the tree.
 // This function only returns false for a name once.
 func (t *Tree) hasSeenImport(name string) bool {
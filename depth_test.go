@@ -2,6 +2,10 @@ package depth
 
 import (
 	"go/build"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -46,6 +50,56 @@ func TestTree_Resolve(t *testing.T) {
 	}
 }
 
+func TestTree_ResolveDir(t *testing.T) {
+	tr := Tree{Dir: "cmd/depth"}
+	if err := tr.Resolve("."); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(tr.Root.SrcDir, "cmd/depth") {
+		t.Fatalf("Unexpected SrcDir, expected suffix=%v, got=%v", "cmd/depth", tr.Root.SrcDir)
+	} else if tr.Root.Raw.Name != "main" {
+		t.Fatalf("Unexpected package, expected=%v, got=%v", "main", tr.Root.Raw.Name)
+	}
+}
+
+func TestTree_ResolveAll(t *testing.T) {
+	const prefix = "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/"
+
+	tr := Tree{Dir: "cmd/depth/testdata/rdeps"}
+	if err := tr.ResolveAll("./..."); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{prefix + "a", prefix + "b", prefix + "c"}
+	if len(tr.Roots) != len(expected) {
+		t.Fatalf("Unexpected Roots length, expected=%v, got=%v", len(expected), len(tr.Roots))
+	}
+	for i, r := range tr.Roots {
+		if r.Name != expected[i] {
+			t.Fatalf("[%v] Unexpected root, expected=%v, got=%v", i, expected[i], r.Name)
+		} else if len(r.Deps) != len(r.Raw.Imports) {
+			t.Fatalf("[%v] Unexpected deps, expected=%v, got=%v", r.Name, len(r.Raw.Imports), len(r.Deps))
+		}
+	}
+
+	if tr.Root != tr.Roots[0] {
+		t.Fatalf("Unexpected Root, expected=%v, got=%v", tr.Roots[0].Name, tr.Root.Name)
+	} else if len(tr.Graph.Roots()) != len(expected) {
+		t.Fatalf("Unexpected Graph roots, expected=%v, got=%v", len(expected), len(tr.Graph.Roots()))
+	} else if sum := tr.Summary(); sum.Total() != 0 {
+		t.Fatalf("Unexpected Summary, expected=%v, got=%v", 0, sum.Total())
+	}
+
+	if err := tr.ResolveAll("./a/notreal..."); err != ErrNoPackages {
+		t.Fatalf("Unexpected error, expected=%v, got=%v", ErrNoPackages, err)
+	}
+
+	if err := tr.ResolveAll("github.com/KyleBanks/notreal"); err != ErrRootPkgNotResolved {
+		t.Fatalf("Unexpected error, expected=%v, got=%v", ErrRootPkgNotResolved, err)
+	}
+}
+
 func TestTree_shouldResolveInternal(t *testing.T) {
 	var pt Tree
 	pt.Root = &Pkg{}
@@ -65,6 +119,42 @@ func TestTree_shouldResolveInternal(t *testing.T) {
 	}
 }
 
+func TestTree_ResolveMainTest(t *testing.T) {
+	for _, workers := range []int{0, 8} {
+		tr := Tree{
+			ResolveMainTest: true,
+			Workers:         workers,
+			Importer: mockModuleImporter{
+				MockImporter: mockGraphImporter(map[string][]string{
+					"app":             {"app/lib", "example.com/dep"},
+					"app_test":        {"example.com/assert"},
+					"app/lib":         {},
+					"app/lib_test":    {"example.com/mock"},
+					"example.com/dep": {},
+					// The test imports of other modules aren't resolved.
+					"example.com/dep_test": {"example.com/missing"},
+					"example.com/assert":   {},
+					"example.com/mock":     {},
+				}),
+				modules: map[string]*Module{
+					"app":     {Path: "app", Main: true},
+					"app/lib": {Path: "app", Main: true},
+				},
+			},
+		}
+		if err := tr.Resolve("app"); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"app", "app/lib", "example.com/assert", "example.com/dep", "example.com/mock"}
+		if nodes := nodeNames(tr.Graph.Nodes()); !reflect.DeepEqual(nodes, expected) {
+			t.Fatalf("[%v] Unexpected Nodes, expected=%v, got=%v", workers, expected, nodes)
+		} else if err := tr.Err(); err != nil {
+			t.Fatalf("[%v] Unexpected Err, expected=nil, got=%v", workers, err)
+		}
+	}
+}
+
 func TestTree_isAtMaxDepth(t *testing.T) {
 	tests := []struct {
 		maxDepth int
@@ -105,3 +195,48 @@ func TestTree_hasSeenImport(t *testing.T) {
 		t.Fatalf("Expected true to be returned after the import name has been seen, got=false")
 	}
 }
+
+func TestReadTree(t *testing.T) {
+	r := strings.NewReader(`{
+		"name": "root",
+		"deps": [
+			{"name": "strings", "internal": true, "resolved": true},
+			{"name": "testing", "internal": true, "resolved": true, "test": true},
+			{"name": "github.com/foo/bar", "resolved": true, "deps": [
+				{"name": "strings", "internal": true, "resolved": true}
+			]}
+		]
+	}`)
+
+	tr, err := ReadTree(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.Root.Name != "root" || len(tr.Root.Deps) != 3 {
+		t.Fatalf("Unexpected Root, got=%+v", tr.Root)
+	} else if bar := tr.Root.Deps[2]; bar.Tree != tr || bar.Deps[0].Parent == nil || bar.Deps[0].Parent.Name != "github.com/foo/bar" {
+		t.Fatal("Expected Tree and Parent to be linked")
+	} else if sum := tr.Summary(); sum != (Summary{Internal: 2, External: 1, Testing: 1}) {
+		t.Fatalf("Unexpected Summary, got=%+v", sum)
+	} else if importers := nodeNames(tr.Graph.ImportersOf("strings")); !reflect.DeepEqual(importers, []string{"github.com/foo/bar", "root"}) {
+		t.Fatalf("Unexpected ImportersOf, got=%v", importers)
+	}
+
+	if _, err := ReadTree(strings.NewReader("{")); err == nil {
+		t.Fatal("Expected error for invalid JSON")
+	}
+}
+
+// writeFiles writes files, relative to the directory provided.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
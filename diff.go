@@ -0,0 +1,126 @@
+package depth
+
+import (
+	"strings"
+)
+
+// TreeDiff describes how the dependencies of a Tree changed.
+type TreeDiff struct {
+	// Added contains the dependencies that are only present in the new Tree.
+	Added []string
+	// Removed contains the dependencies that are only present in the old Tree.
+	Removed []string
+	// Moved contains the dependencies present in both Trees that are reached through
+	// a different chain of imports.
+	Moved []Move
+
+	// AddedEdges and RemovedEdges contain the imports that are only present in the new
+	// and old Tree, respectively.
+	AddedEdges   []Edge
+	RemovedEdges []Edge
+
+	Before Summary
+	After  Summary
+}
+
+// Move describes a dependency whose shortest chain of imports from the root changed.
+type Move struct {
+	Name   string
+	Before []string
+	After  []string
+}
+
+// Empty returns true if the dependencies of the Trees are identical.
+func (d *TreeDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Diff compares the dependencies of two resolved Trees, such as a package before and
+// after a change.
+//
+// The roots of the Trees are not compared, so two revisions of the same package can
+// be compared even if they are resolved from different directories. Imports by the
+// roots are compared regardless of the root names when both Trees have a single root.
+func Diff(a, b *Tree) *TreeDiff {
+	d := TreeDiff{
+		Before: a.Summary(),
+		After:  b.Summary(),
+	}
+
+	for _, n := range b.Graph.Nodes() {
+		if isRoot(b.Graph, n.Name) {
+			continue
+		}
+
+		if a.Graph.Node(n.Name) == nil || isRoot(a.Graph, n.Name) {
+			d.Added = append(d.Added, n.Name)
+			continue
+		}
+
+		before, after := a.Graph.RootPath(n.Name), b.Graph.RootPath(n.Name)
+		if before != nil && after != nil && strings.Join(before[1:], " ") != strings.Join(after[1:], " ") {
+			d.Moved = append(d.Moved, Move{Name: n.Name, Before: before, After: after})
+		}
+	}
+
+	for _, n := range a.Graph.Nodes() {
+		if !isRoot(a.Graph, n.Name) && (b.Graph.Node(n.Name) == nil || isRoot(b.Graph, n.Name)) {
+			d.Removed = append(d.Removed, n.Name)
+		}
+	}
+
+	anyRoot := len(a.Graph.roots) == 1 && len(b.Graph.roots) == 1
+	d.AddedEdges = edgesMissingFrom(b, a, anyRoot)
+	d.RemovedEdges = edgesMissingFrom(a, b, anyRoot)
+	return &d
+}
+
+// edgesMissingFrom returns the imports of the first Tree that aren't present in the
+// second. If anyRoot is true, imports by the roots are compared with each other
+// regardless of their names.
+func edgesMissingFrom(a, b *Tree, anyRoot bool) []Edge {
+	present := make(map[edgeKey]struct{})
+	for _, e := range b.Graph.Edges() {
+		present[diffEdgeKey(b.Graph, e, anyRoot)] = struct{}{}
+	}
+
+	var missing []Edge
+	for _, e := range a.Graph.Edges() {
+		if _, ok := present[diffEdgeKey(a.Graph, e, anyRoot)]; !ok {
+			missing = append(missing, e)
+		}
+	}
+	return missing
+}
+
+// edgeKey identifies an Edge by the packages it connects.
+type edgeKey struct {
+	from, to string
+}
+
+// diffEdgeKey returns the key of an Edge used to compare it across Graphs. If anyRoot
+// is true, the roots of the Graph are replaced by an empty name.
+func diffEdgeKey(g *Graph, e Edge, anyRoot bool) edgeKey {
+	k := edgeKey{from: e.From, to: e.To}
+	if !anyRoot {
+		return k
+	}
+	if isRoot(g, k.from) {
+		k.from = ""
+	}
+	if isRoot(g, k.to) {
+		k.to = ""
+	}
+	return k
+}
+
+// isRoot returns true if the package is a root of the Graph.
+func isRoot(g *Graph, name string) bool {
+	for _, r := range g.roots {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
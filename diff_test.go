@@ -0,0 +1,103 @@
+package depth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"root": {"b", "c"},
+			"b":    {"d"},
+			"c":    {"e"},
+			"d":    {},
+			"e":    {},
+		}),
+	}
+	if err := a.Resolve("root"); err != nil {
+		t.Fatal(err)
+	}
+
+	b := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"root2": {"b", "c", "f"},
+			"b":     {},
+			"c":     {"d"},
+			"d":     {},
+			"f":     {},
+		}),
+	}
+	if err := b.Resolve("root2"); err != nil {
+		t.Fatal(err)
+	}
+
+	d := Diff(&a, &b)
+	if !reflect.DeepEqual(d.Added, []string{"f"}) {
+		t.Fatalf("Unexpected Added, got=%v", d.Added)
+	} else if !reflect.DeepEqual(d.Removed, []string{"e"}) {
+		t.Fatalf("Unexpected Removed, got=%v", d.Removed)
+	}
+
+	expectedMoved := []Move{{Name: "d", Before: []string{"root", "b", "d"}, After: []string{"root2", "c", "d"}}}
+	if !reflect.DeepEqual(d.Moved, expectedMoved) {
+		t.Fatalf("Unexpected Moved, expected=%+v, got=%+v", expectedMoved, d.Moved)
+	}
+
+	expectedAdded := []Edge{{From: "c", To: "d"}, {From: "root2", To: "f"}}
+	if !reflect.DeepEqual(d.AddedEdges, expectedAdded) {
+		t.Fatalf("Unexpected AddedEdges, expected=%v, got=%v", expectedAdded, d.AddedEdges)
+	}
+	expectedRemoved := []Edge{{From: "b", To: "d"}, {From: "c", To: "e"}}
+	if !reflect.DeepEqual(d.RemovedEdges, expectedRemoved) {
+		t.Fatalf("Unexpected RemovedEdges, expected=%v, got=%v", expectedRemoved, d.RemovedEdges)
+	}
+
+	if d.Before.Total() != 4 || d.After.Total() != 4 {
+		t.Fatalf("Unexpected Summaries, got=%+v and %+v", d.Before, d.After)
+	} else if d.Empty() {
+		t.Fatal("Expected non-empty TreeDiff")
+	}
+
+	if !Diff(&a, &a).Empty() {
+		t.Fatal("Expected empty TreeDiff for identical Trees")
+	}
+}
+
+func TestDiffRoots(t *testing.T) {
+	a := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"r1": {"x"},
+			"r2": {},
+			"x":  {},
+		}),
+	}
+	if err := a.ResolveAll("r1", "r2"); err != nil {
+		t.Fatal(err)
+	}
+
+	b := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"r1": {},
+			"r2": {"x"},
+			"x":  {},
+		}),
+	}
+	if err := b.ResolveAll("r1", "r2"); err != nil {
+		t.Fatal(err)
+	}
+
+	d := Diff(&a, &b)
+	if len(d.Added) != 0 || len(d.Removed) != 0 {
+		t.Fatalf("Unexpected Added or Removed, got=%v and %v", d.Added, d.Removed)
+	}
+
+	expectedAdded := []Edge{{From: "r2", To: "x"}}
+	if !reflect.DeepEqual(d.AddedEdges, expectedAdded) {
+		t.Fatalf("Unexpected AddedEdges, expected=%v, got=%v", expectedAdded, d.AddedEdges)
+	}
+	expectedRemoved := []Edge{{From: "r1", To: "x"}}
+	if !reflect.DeepEqual(d.RemovedEdges, expectedRemoved) {
+		t.Fatalf("Unexpected RemovedEdges, expected=%v, got=%v", expectedRemoved, d.RemovedEdges)
+	}
+}
@@ -0,0 +1,153 @@
+package depth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/build"
+	"go/scanner"
+	"strings"
+)
+
+// ErrorKind classifies why a Pkg could not be resolved.
+type ErrorKind string
+
+const (
+	// KindMissing indicates that the package could not be found.
+	KindMissing ErrorKind = "missing"
+	// KindNoGoFiles indicates that the package directory contains no Go files.
+	KindNoGoFiles ErrorKind = "no-go-files"
+	// KindBuildConstraints indicates that every Go file in the package is excluded
+	// by build constraints.
+	KindBuildConstraints ErrorKind = "build-constraints"
+	// KindParseError indicates that the Go files of the package could not be parsed.
+	KindParseError ErrorKind = "parse-error"
+	// KindUnknown indicates any other failure to import the package.
+	KindUnknown ErrorKind = "unknown"
+)
+
+// ResolveError describes why a Pkg could not be resolved.
+//
+// When decoded from JSON, such as by ReadTree, only the message of the underlying
+// error is known, and the Name is that of the Pkg containing the ResolveError.
+type ResolveError struct {
+	Name string
+	Kind ErrorKind
+	Err  error
+}
+
+// newResolveError classifies the error returned when importing the named package.
+//
+// The package returned alongside the error, if any, is used to tell packages excluded
+// by build constraints apart from those without any Go files.
+func newResolveError(name string, pkg *build.Package, err error) *ResolveError {
+	return &ResolveError{Name: name, Kind: errorKind(pkg, err), Err: err}
+}
+
+// errorKind returns the ErrorKind of an error returned by an Importer.
+func errorKind(pkg *build.Package, err error) ErrorKind {
+	switch err.(type) {
+	case *build.NoGoError:
+		if pkg != nil && len(pkg.IgnoredGoFiles) > 0 {
+			return KindBuildConstraints
+		}
+		return KindNoGoFiles
+	case *build.MultiplePackageError, scanner.ErrorList, *scanner.Error:
+		return KindParseError
+	}
+
+	// Importers such as the GoListImporter only report the error message.
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "build constraints exclude all Go files"):
+		return KindBuildConstraints
+	case strings.Contains(msg, "no Go files"), strings.Contains(msg, "no buildable Go source files"):
+		return KindNoGoFiles
+	case strings.Contains(msg, "expected 'package'"), strings.Contains(msg, "syntax error"), strings.Contains(msg, "found packages"):
+		return KindParseError
+	case pkg == nil || pkg.Dir == "":
+		return KindMissing
+	}
+
+	return KindUnknown
+}
+
+// Error implements the error interface.
+func (e *ResolveError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%v (%v)", e.Name, e.Kind)
+	}
+	return fmt.Sprintf("%v (%v): %v", e.Name, e.Kind, e.Err)
+}
+
+// Unwrap returns the underlying error returned by the Importer.
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the kind and message
+// of the error.
+func (e *ResolveError) MarshalJSON() ([]byte, error) {
+	var msg string
+	if e.Err != nil {
+		msg = e.Err.Error()
+	}
+
+	return json.Marshal(resolveErrorJSON{e.Kind, msg})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, restoring the kind of the
+// error and an error containing its message.
+func (e *ResolveError) UnmarshalJSON(b []byte) error {
+	var v resolveErrorJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	e.Kind = v.Kind
+	e.Err = nil
+	if v.Message != "" {
+		e.Err = errors.New(v.Message)
+	}
+	return nil
+}
+
+// resolveErrorJSON is the JSON representation of a ResolveError.
+type resolveErrorJSON struct {
+	Kind    ErrorKind `json:"kind"`
+	Message string    `json:"message"`
+}
+
+// ResolveErrors is the aggregate of every package within a Tree that could not be
+// resolved.
+type ResolveErrors []*ResolveError
+
+// Error implements the error interface.
+func (e ResolveErrors) Error() string {
+	names := make([]string, len(e))
+	for i, err := range e {
+		names[i] = fmt.Sprintf("%v (%v)", err.Name, err.Kind)
+	}
+
+	return fmt.Sprintf("%d packages could not be resolved: %v", len(e), strings.Join(names, ", "))
+}
+
+// Err returns a ResolveErrors containing each package within the Tree that could not
+// be resolved, or nil if every package was resolved.
+func (t *Tree) Err() error {
+	if t.Graph == nil {
+		return nil
+	}
+
+	var errs ResolveErrors
+	for _, n := range t.Graph.Nodes() {
+		if n.Pkg.Err != nil {
+			errs = append(errs, n.Pkg.Err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
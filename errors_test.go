@@ -0,0 +1,128 @@
+package depth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go/build"
+	"go/scanner"
+	"testing"
+)
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		pkg      *build.Package
+		err      error
+		expected ErrorKind
+	}{
+		{nil, &build.NoGoError{Dir: "dir"}, KindNoGoFiles},
+		{&build.Package{IgnoredGoFiles: []string{"a.go"}}, &build.NoGoError{Dir: "dir"}, KindBuildConstraints},
+		{&build.Package{}, &build.MultiplePackageError{}, KindParseError},
+		{&build.Package{}, scanner.ErrorList{}, KindParseError},
+		{nil, errors.New("package notreal is not in std"), KindMissing},
+		{nil, errors.New("build constraints exclude all Go files in dir"), KindBuildConstraints},
+		{nil, errors.New("no Go files in dir"), KindNoGoFiles},
+		{nil, errors.New("dir/a.go:1:1: expected 'package', found 'EOF'"), KindParseError},
+		{&build.Package{Dir: "dir"}, errors.New("something else"), KindUnknown},
+	}
+
+	for idx, tt := range tests {
+		if out := errorKind(tt.pkg, tt.err); out != tt.expected {
+			t.Fatalf("[%v] Unexpected errorKind, expected=%v, got=%v", idx, tt.expected, out)
+		}
+	}
+}
+
+func TestResolveError(t *testing.T) {
+	cause := errors.New("cause")
+	e := newResolveError("name", nil, cause)
+
+	if e.Error() != "name (missing): cause" {
+		t.Fatalf("Unexpected Error, got=%v", e.Error())
+	} else if !errors.Is(e, cause) {
+		t.Fatal("Expected ResolveError to wrap its cause")
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	} else if string(b) != `{"kind":"missing","message":"cause"}` {
+		t.Fatalf("Unexpected JSON, got=%s", b)
+	}
+
+	var decoded ResolveError
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	} else if decoded.Kind != KindMissing || decoded.Err == nil || decoded.Err.Error() != "cause" {
+		t.Fatalf("Unexpected decoded ResolveError, got=%+v", decoded)
+	}
+
+	// ResolveErrors without an underlying error can still be formatted.
+	empty := ResolveError{Name: "name", Kind: KindUnknown}
+	if empty.Error() != "name (unknown)" {
+		t.Fatalf("Unexpected Error, got=%v", empty.Error())
+	} else if b, err := json.Marshal(&empty); err != nil || string(b) != `{"kind":"unknown","message":""}` {
+		t.Fatalf("Unexpected JSON, got=%s, err=%v", b, err)
+	}
+}
+
+func TestReadTree_errors(t *testing.T) {
+	tr := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"a": {"b"},
+		}),
+	}
+	if err := tr.Resolve("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(tr.Root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadTree(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Err() == nil || decoded.Err().Error() != tr.Err().Error() {
+		t.Fatalf("Unexpected Err, expected=%v, got=%v", tr.Err(), decoded.Err())
+	} else if e := decoded.Root.Deps[0].Err; e.Name != "b" || e.Err.Error() != tr.Root.Deps[0].Err.Err.Error() {
+		t.Fatalf("Unexpected ResolveError, got=%v", e)
+	}
+
+	again, err := json.Marshal(decoded.Root)
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(again, b) {
+		t.Fatalf("Unexpected JSON after a round trip, expected=%s, got=%s", b, again)
+	}
+}
+
+func TestTree_Err(t *testing.T) {
+	tr := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"a": {"b", "c", "d"},
+			"b": {"c"},
+		}),
+	}
+	if err := tr.Resolve("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	errs, ok := tr.Err().(ResolveErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("Unexpected Err, expected 2 ResolveErrors, got=%v", tr.Err())
+	} else if errs[0].Name != "c" || errs[0].Kind != KindNoGoFiles || errs[1].Name != "d" {
+		t.Fatalf("Unexpected ResolveErrors, got=%v", errs)
+	} else if errs.Error() != "2 packages could not be resolved: c (no-go-files), d (no-go-files)" {
+		t.Fatalf("Unexpected Error, got=%v", errs.Error())
+	}
+
+	if err := tr.Resolve("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Tree{}).Err(); err != nil {
+		t.Fatalf("Unexpected Err for unresolved Tree, expected=nil, got=%v", err)
+	}
+}
@@ -0,0 +1,304 @@
+package depth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Module describes the Go module that provides a Pkg.
+type Module struct {
+	Path    string  `json:"path"`
+	Version string  `json:"version,omitempty"`
+	Replace *Module `json:"replace,omitempty"`
+	Main    bool    `json:"main,omitempty"`
+	Dir     string  `json:"-"`
+}
+
+// String returns a string representation of the Module containing its path, version
+// and replacement, if any.
+func (m *Module) String() string {
+	s := m.Path
+	if m.Version != "" {
+		s += " " + m.Version
+	}
+
+	if m.Replace != nil {
+		s += " => " + m.Replace.String()
+	}
+
+	return s
+}
+
+// ModuleImporter defines an Importer that also knows which module provides each
+// package it imports.
+type ModuleImporter interface {
+	Importer
+
+	// Module returns the Module providing the import path provided, or nil if it
+	// is not provided by a module (ie. the stdlib).
+	Module(importPath string) *Module
+}
+
+// GoListImporter is a module-aware Importer backed by `go list -json -deps`.
+//
+// Unlike build.Default, it honours go.mod, replace directives and workspaces, and
+// reports the Module of each package it imports. Results are cached for the lifetime
+// of the GoListImporter, so a single instance should only be used within one module.
+type GoListImporter struct {
+	// Env contains additional environment variables, in the form "key=value",
+	// provided to the go command.
+	Env []string
+
+	// Context, if set, provides the build tags, GOOS, GOARCH and cgo setting
+	// used by the go command.
+	Context *build.Context
+
+	mu   sync.Mutex
+	pkgs map[string]*goListPackage
+}
+
+// goListPackage is the subset of the `go list -json` output used by the GoListImporter.
+type goListPackage struct {
+	Dir          string
+	ImportPath   string
+	Name         string
+	Doc          string
+	Root         string
+	Goroot       bool
+	DepOnly      bool
+	Module       *goListModule
+	GoFiles      []string
+	CgoFiles     []string
+	TestGoFiles  []string
+	XTestGoFiles []string
+
+	IgnoredGoFiles []string
+	InvalidGoFiles []string
+
+	Imports      []string
+	TestImports  []string
+	XTestImports []string
+
+	Error *struct {
+		Err string
+	}
+
+	// The positions of each import are not reported by go list, so they are
+	// parsed from the source files when first needed.
+	posOnce        sync.Once
+	importPos      map[string][]token.Position
+	testImportPos  map[string][]token.Position
+	xTestImportPos map[string][]token.Position
+}
+
+// goListModule is the module information reported by `go list -json`.
+type goListModule struct {
+	Path    string
+	Version string
+	Replace *goListModule
+	Main    bool
+	Dir     string
+}
+
+// module converts the listed module into a Module.
+func (m *goListModule) module() *Module {
+	if m == nil {
+		return nil
+	}
+
+	return &Module{
+		Path:    m.Path,
+		Version: m.Version,
+		Replace: m.Replace.module(),
+		Main:    m.Main,
+		Dir:     m.Dir,
+	}
+}
+
+// Import implements the Importer interface by running `go list` for the package name
+// in the source directory provided.
+func (g *GoListImporter) Import(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+	if p := g.cached(name); p != nil {
+		return p.buildPackage(im)
+	}
+
+	pkgs, err := g.list(name, srcDir)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.pkgs == nil {
+		g.pkgs = make(map[string]*goListPackage)
+	}
+
+	var target *goListPackage
+	for _, p := range pkgs {
+		// Prefer the first result for an import path, as later results may be
+		// the dependency-only listing of the same package.
+		if _, ok := g.pkgs[p.ImportPath]; !ok {
+			g.pkgs[p.ImportPath] = p
+		}
+		if p.ImportPath == name || (!p.DepOnly && target == nil) {
+			target = p
+		}
+	}
+
+	if target == nil {
+		return nil, fmt.Errorf("cannot find package %q in %v", name, srcDir)
+	}
+	return target.buildPackage(im)
+}
+
+// Module implements the ModuleImporter interface, returning the Module of a
+// previously imported package.
+func (g *GoListImporter) Module(importPath string) *Module {
+	if p := g.cached(importPath); p != nil {
+		return p.Module.module()
+	}
+	return nil
+}
+
+// cached returns the previously listed package with the import path provided, or nil.
+//
+// Relative and absolute paths are never cached, as they depend on the source directory.
+func (g *GoListImporter) cached(name string) *goListPackage {
+	if build.IsLocalImport(name) || filepath.IsAbs(name) {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.pkgs[name]
+}
+
+// list runs `go list` for the package name and all of its dependencies.
+func (g *GoListImporter) list(name, srcDir string) ([]*goListPackage, error) {
+	args := []string{"list", "-e", "-json", "-deps"}
+	env := os.Environ()
+	if c := g.Context; c != nil {
+		if len(c.BuildTags) > 0 {
+			args = append(args, "-tags", strings.Join(c.BuildTags, ","))
+		}
+
+		cgo := "0"
+		if c.CgoEnabled {
+			cgo = "1"
+		}
+		env = append(env, "GOOS="+c.GOOS, "GOARCH="+c.GOARCH, "CGO_ENABLED="+cgo)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", append(args, "--", name)...)
+	cmd.Dir = srcDir
+	cmd.Env = append(env, g.Env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go list %v: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var pkgs []*goListPackage
+	d := json.NewDecoder(&stdout)
+	for {
+		var p goListPackage
+		if err := d.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, &p)
+	}
+
+	return pkgs, nil
+}
+
+// buildPackage converts the listed package into a build.Package, returning the listing
+// error, if any.
+//
+// As with build.Import, only the location of the package is returned when the
+// build.FindOnly mode is provided.
+func (p *goListPackage) buildPackage(im build.ImportMode) (*build.Package, error) {
+	if p.Error != nil && p.Dir == "" {
+		return nil, errors.New(p.Error.Err)
+	}
+
+	if im&build.FindOnly != 0 {
+		return &build.Package{
+			Dir:        p.Dir,
+			ImportPath: p.ImportPath,
+			Root:       p.Root,
+			Goroot:     p.Goroot,
+		}, nil
+	}
+
+	p.posOnce.Do(p.parseImportPos)
+	pkg := &build.Package{
+		Dir:            p.Dir,
+		Name:           p.Name,
+		Doc:            p.Doc,
+		ImportPath:     p.ImportPath,
+		Root:           p.Root,
+		Goroot:         p.Goroot,
+		GoFiles:        p.GoFiles,
+		CgoFiles:       p.CgoFiles,
+		IgnoredGoFiles: p.IgnoredGoFiles,
+		InvalidGoFiles: p.InvalidGoFiles,
+		TestGoFiles:    p.TestGoFiles,
+		XTestGoFiles:   p.XTestGoFiles,
+		Imports:        p.Imports,
+		TestImports:    p.TestImports,
+		XTestImports:   p.XTestImports,
+		ImportPos:      p.importPos,
+		TestImportPos:  p.testImportPos,
+		XTestImportPos: p.xTestImportPos,
+	}
+
+	if p.Error != nil {
+		return pkg, errors.New(p.Error.Err)
+	}
+	return pkg, nil
+}
+
+// parseImportPos parses the imports of the source files of the package to find the
+// position of each import. Files that cannot be parsed are skipped.
+func (p *goListPackage) parseImportPos() {
+	fset := token.NewFileSet()
+	parse := func(files ...[]string) map[string][]token.Position {
+		pos := make(map[string][]token.Position)
+		for _, names := range files {
+			for _, name := range names {
+				f, err := parser.ParseFile(fset, filepath.Join(p.Dir, name), nil, parser.ImportsOnly)
+				if err != nil {
+					continue
+				}
+
+				for _, imp := range f.Imports {
+					path, err := strconv.Unquote(imp.Path.Value)
+					if err != nil {
+						continue
+					}
+					pos[path] = append(pos[path], fset.Position(imp.Pos()))
+				}
+			}
+		}
+		return pos
+	}
+
+	p.importPos = parse(p.GoFiles, p.CgoFiles)
+	p.testImportPos = parse(p.TestGoFiles)
+	p.xTestImportPos = parse(p.XTestGoFiles)
+}
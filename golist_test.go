@@ -0,0 +1,95 @@
+package depth
+
+import (
+	"go/build"
+	"reflect"
+	"testing"
+)
+
+func TestModule_String(t *testing.T) {
+	tests := []struct {
+		m        Module
+		expected string
+	}{
+		{Module{Path: "github.com/foo/bar"}, "github.com/foo/bar"},
+		{Module{Path: "github.com/foo/bar", Version: "v1.2.3"}, "github.com/foo/bar v1.2.3"},
+		{Module{Path: "github.com/foo/bar", Version: "v1.2.3", Replace: &Module{Path: "../bar"}}, "github.com/foo/bar v1.2.3 => ../bar"},
+		{Module{Path: "github.com/foo/bar", Version: "v1.2.3", Replace: &Module{Path: "github.com/baz/bar", Version: "v1.0.0"}}, "github.com/foo/bar v1.2.3 => github.com/baz/bar v1.0.0"},
+	}
+
+	for idx, tt := range tests {
+		if out := tt.m.String(); out != tt.expected {
+			t.Fatalf("[%v] Unexpected String, expected=%v, got=%v", idx, tt.expected, out)
+		}
+	}
+}
+
+func TestGoListImporter_Import(t *testing.T) {
+	var g GoListImporter
+
+	pkg, err := g.Import("github.com/KyleBanks/depth", ".", 0)
+	if err != nil {
+		t.Fatal(err)
+	} else if pkg.ImportPath != "github.com/KyleBanks/depth" {
+		t.Fatalf("Unexpected ImportPath, expected=%v, got=%v", "github.com/KyleBanks/depth", pkg.ImportPath)
+	} else if len(pkg.Imports) == 0 {
+		t.Fatal("Expected positive number of Imports")
+	}
+
+	m := g.Module(pkg.ImportPath)
+	if m == nil || m.Path != "github.com/KyleBanks/depth" || !m.Main {
+		t.Fatalf("Unexpected Module, expected main module github.com/KyleBanks/depth, got=%v", m)
+	}
+
+	// Dependencies are cached from the first listing.
+	if g.cached("strings") == nil {
+		t.Fatal("Expected dependency 'strings' to be cached")
+	}
+	pkg, err = g.Import("strings", ".", build.FindOnly)
+	if err != nil {
+		t.Fatal(err)
+	} else if !pkg.Goroot {
+		t.Fatal("Expected strings to be in GOROOT")
+	} else if len(pkg.Imports) != 0 {
+		t.Fatalf("Unexpected Imports for FindOnly, expected none, got=%v", pkg.Imports)
+	} else if g.Module("strings") != nil {
+		t.Fatal("Expected nil Module for stdlib package")
+	}
+
+	if _, err := g.Import("notreal", ".", 0); err == nil {
+		t.Fatal("Expected error for unknown package")
+	}
+}
+
+func TestGoListImporter_ImportContext(t *testing.T) {
+	const name = "github.com/KyleBanks/depth/cmd/depth/testdata/platforms"
+
+	ctx := build.Default
+	ctx.GOOS = "windows"
+	ctx.BuildTags = []string{"custom"}
+	ctx.CgoEnabled = false
+	g := GoListImporter{Context: &ctx}
+
+	pkg, err := g.Import(name, ".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"fmt", name + "/custom", name + "/windows"}
+	if !reflect.DeepEqual(pkg.Imports, expected) {
+		t.Fatalf("Unexpected Imports, expected=%v, got=%v", expected, pkg.Imports)
+	}
+}
+
+func TestTree_ResolveGoList(t *testing.T) {
+	tr := Tree{Importer: &GoListImporter{}}
+	if err := tr.Resolve("./cmd/depth"); err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.Root.Name != "github.com/KyleBanks/depth/cmd/depth" {
+		t.Fatalf("Unexpected Root name, expected=%v, got=%v", "github.com/KyleBanks/depth/cmd/depth", tr.Root.Name)
+	} else if tr.Root.Module == nil || tr.Root.Module.Path != "github.com/KyleBanks/depth" {
+		t.Fatalf("Unexpected Root Module, got=%v", tr.Root.Module)
+	}
+}
@@ -0,0 +1,221 @@
+package depth
+
+import (
+	"sort"
+	"sync"
+)
+
+// Graph is a deduplicated view of a resolved Tree, containing a single Node for each
+// import path and an Edge for each import.
+//
+// Unlike the Pkg tree, where a package imported by several parents appears as several
+// copies, the Graph can be queried for every importer of a package.
+type Graph struct {
+	roots     []string
+	platforms []string
+	nodes     map[string]*Node
+	imports   map[string][]Edge
+	importers map[string][]Edge
+
+	weightMu   sync.Mutex
+	weights    map[string]Weight
+	transitive map[string]Weight
+}
+
+// Node represents a single package within a Graph.
+type Node struct {
+	Name     string
+	Internal bool
+	Resolved bool
+
+	// Test is true when the package is only reachable from the roots of the Graph
+	// through test imports.
+	Test bool
+
+	// Pkg is the copy of the package within the Tree that its dependencies were
+	// resolved on.
+	Pkg *Pkg
+
+	// Packages contains the Nodes collapsed into the Node by Group.
+	Packages []*Node
+}
+
+// Edge represents an import from one package to another.
+type Edge struct {
+	From string
+	To   string
+
+	// Test is true when the import is only used for testing.
+	Test bool
+
+	// Platforms contains the platforms, such as "linux/amd64", that the import
+	// applies to when the Graph was resolved by ResolvePlatforms.
+	Platforms []string
+
+	// Files contains the positions of the import within the source files of the
+	// importing package.
+	Files []Position
+
+	// Symbols contains the exported identifiers of the imported package referenced
+	// by the importing package, when the Tree is resolved with ResolveSymbols.
+	Symbols []string
+
+	// Imports contains the Edges between packages collapsed into the Edge by Group.
+	Imports []Edge
+}
+
+// newGraph builds a Graph from the resolved root Pkgs provided.
+func newGraph(roots ...*Pkg) *Graph {
+	g := &Graph{
+		nodes:     make(map[string]*Node),
+		imports:   make(map[string][]Edge),
+		importers: make(map[string][]Edge),
+	}
+
+	for _, r := range roots {
+		g.roots = append(g.roots, r.Name)
+		g.add(r)
+	}
+	g.markTest()
+
+	return g
+}
+
+// add recursively adds the Pkg, its dependencies and their imports to the Graph.
+func (g *Graph) add(p *Pkg) {
+	n, ok := g.nodes[p.Name]
+	if !ok {
+		n = &Node{Name: p.Name, Internal: p.Internal, Resolved: p.Resolved, Pkg: p}
+		g.nodes[p.Name] = n
+	} else if !isFullyImported(n.Pkg) && isFullyImported(p) {
+		n.Pkg = p
+		n.Resolved = p.Resolved
+	}
+
+	for i := range p.Deps {
+		d := &p.Deps[i]
+		g.addEdge(Edge{From: p.Name, To: d.Name, Test: d.Test, Files: d.Files, Symbols: d.Symbols})
+		g.add(d)
+	}
+}
+
+// addEdge adds the Edge to the Graph, unless it is already present.
+func (g *Graph) addEdge(e Edge) {
+	for _, existing := range g.imports[e.From] {
+		if existing.To == e.To {
+			return
+		}
+	}
+
+	g.imports[e.From] = append(g.imports[e.From], e)
+	g.importers[e.To] = append(g.importers[e.To], e)
+}
+
+// markTest flags each Node that is only reachable from the roots through test imports.
+func (g *Graph) markTest() {
+	reached := make(map[string]struct{})
+	queue := append([]string{}, g.roots...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if _, ok := reached[name]; ok {
+			continue
+		}
+		reached[name] = struct{}{}
+
+		for _, e := range g.imports[name] {
+			if !e.Test {
+				queue = append(queue, e.To)
+			}
+		}
+	}
+
+	for name, n := range g.nodes {
+		_, ok := reached[name]
+		n.Test = !ok
+	}
+}
+
+// Roots returns the Nodes that the Graph was resolved from.
+func (g *Graph) Roots() []*Node {
+	nodes := make([]*Node, 0, len(g.roots))
+	for _, name := range g.roots {
+		nodes = append(nodes, g.nodes[name])
+	}
+	return nodes
+}
+
+// Node returns the Node with the import path provided, or nil if it isn't part
+// of the Graph.
+func (g *Graph) Node(name string) *Node {
+	return g.nodes[name]
+}
+
+// Nodes returns every Node in the Graph, with internal packages ahead of external
+// packages and sorted by name.
+func (g *Graph) Nodes() []*Node {
+	nodes := make([]*Node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+
+	sortNodes(nodes)
+	return nodes
+}
+
+// Edges returns every Edge in the Graph, ordered by the importing Node.
+func (g *Graph) Edges() []Edge {
+	var edges []Edge
+	for _, n := range g.Nodes() {
+		edges = append(edges, g.imports[n.Name]...)
+	}
+	return edges
+}
+
+// EdgesFrom returns the Edges of the imports of the package provided.
+func (g *Graph) EdgesFrom(name string) []Edge {
+	return g.imports[name]
+}
+
+// Platforms returns the platforms the Graph was resolved for by ResolvePlatforms,
+// or nil if it was resolved for a single platform.
+func (g *Graph) Platforms() []string {
+	return g.platforms
+}
+
+// ImportsOf returns the Nodes directly imported by the package provided.
+func (g *Graph) ImportsOf(name string) []*Node {
+	var nodes []*Node
+	for _, e := range g.imports[name] {
+		nodes = append(nodes, g.nodes[e.To])
+	}
+	return nodes
+}
+
+// ImportersOf returns the Nodes that directly import the package provided.
+func (g *Graph) ImportersOf(name string) []*Node {
+	var nodes []*Node
+	for _, e := range g.importers[name] {
+		nodes = append(nodes, g.nodes[e.From])
+	}
+
+	sortNodes(nodes)
+	return nodes
+}
+
+// isFullyImported returns true if the dependencies of the Pkg were resolved, rather
+// than the Pkg only being located.
+func isFullyImported(p *Pkg) bool {
+	return len(p.Deps) > 0 || (p.Raw != nil && p.Raw.Name != "")
+}
+
+// sortNodes sorts the Nodes in the same order as byInternalAndName.
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Internal != nodes[j].Internal {
+			return nodes[i].Internal
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
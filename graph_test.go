@@ -0,0 +1,99 @@
+package depth
+
+import (
+	"go/build"
+	"reflect"
+	"testing"
+)
+
+// mockGraphImporter returns a MockImporter resolving packages from a map of import
+// paths to their imports. Test imports are declared with a "_test" suffix on the key.
+func mockGraphImporter(imports map[string][]string) MockImporter {
+	return MockImporter{
+		ImportFn: func(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+			deps, ok := imports[name]
+			if !ok {
+				return nil, &build.NoGoError{Dir: name}
+			}
+
+			pkg := &build.Package{ImportPath: name, Dir: name}
+			if im&build.FindOnly == 0 {
+				pkg.Name = name
+				pkg.Imports = deps
+				pkg.TestImports = imports[name+"_test"]
+			}
+			return pkg, nil
+		},
+	}
+}
+
+func TestTree_Graph(t *testing.T) {
+	tr := Tree{
+		ResolveTest: true,
+		Importer: mockGraphImporter(map[string][]string{
+			"a":      {"b", "c"},
+			"a_test": {"e"},
+			"b":      {"d"},
+			"c":      {"d"},
+			"d":      {},
+			"e":      {"d", "f"},
+			"f":      {},
+		}),
+	}
+	if err := tr.Resolve("a"); err != nil {
+		t.Fatal(err)
+	}
+	g := tr.Graph
+
+	if roots := nodeNames(g.Roots()); !reflect.DeepEqual(roots, []string{"a"}) {
+		t.Fatalf("Unexpected Roots, got=%v", roots)
+	}
+	if nodes := nodeNames(g.Nodes()); !reflect.DeepEqual(nodes, []string{"a", "b", "c", "d", "e", "f"}) {
+		t.Fatalf("Unexpected Nodes, got=%v", nodes)
+	}
+	if len(g.Edges()) != 7 {
+		t.Fatalf("Unexpected number of Edges, expected=%v, got=%v", 7, len(g.Edges()))
+	}
+
+	if importers := nodeNames(g.ImportersOf("d")); !reflect.DeepEqual(importers, []string{"b", "c", "e"}) {
+		t.Fatalf("Unexpected ImportersOf, got=%v", importers)
+	}
+	if imports := nodeNames(g.ImportsOf("a")); !reflect.DeepEqual(imports, []string{"b", "c", "e"}) {
+		t.Fatalf("Unexpected ImportsOf, got=%v", imports)
+	}
+
+	// Only the test import of the root, and anything it alone imports, are test-only.
+	for _, n := range g.Nodes() {
+		expected := n.Name == "e" || n.Name == "f"
+		if n.Test != expected {
+			t.Fatalf("[%v] Unexpected Test, expected=%v, got=%v", n.Name, expected, n.Test)
+		}
+	}
+
+	// The Node references the copy of the package that was fully resolved.
+	if d := g.Node("d"); d == nil || !d.Resolved || d.Pkg == nil || !isFullyImported(d.Pkg) {
+		t.Fatalf("Unexpected Node for d, got=%+v", d)
+	}
+	if g.Node("notreal") != nil {
+		t.Fatal("Expected nil Node for unknown package")
+	}
+}
+
+func TestGraph_addEdge(t *testing.T) {
+	g := newGraph()
+	g.addEdge(Edge{From: "a", To: "b"})
+	g.addEdge(Edge{From: "a", To: "b", Test: true})
+
+	if len(g.imports["a"]) != 1 || len(g.importers["b"]) != 1 {
+		t.Fatalf("Expected duplicate Edge to be ignored, got=%v", g.imports["a"])
+	}
+}
+
+// nodeNames returns the names of each Node provided.
+func nodeNames(nodes []*Node) []string {
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	return names
+}
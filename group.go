@@ -0,0 +1,192 @@
+package depth
+
+import (
+	"sort"
+	"strings"
+)
+
+// Grouping determines how Group collapses the packages of a Graph.
+type Grouping string
+
+const (
+	// GroupModule groups packages by the Module providing them, or by repository
+	// when the Module is unknown.
+	GroupModule Grouping = "module"
+	// GroupRepo groups packages by the root of the repository hosting them.
+	GroupRepo Grouping = "repo"
+	// GroupDomain groups packages by the first element of their import path.
+	GroupDomain Grouping = "domain"
+)
+
+// GroupStd is the name of the group containing every internal (stdlib) package.
+const GroupStd = "std"
+
+// repoHosts contains the number of import path elements forming the root of a
+// repository on well-known hosts.
+var repoHosts = map[string]int{
+	"github.com":          3,
+	"gitlab.com":          3,
+	"bitbucket.org":       3,
+	"golang.org":          3,
+	"google.golang.org":   2,
+	"gopkg.in":            2,
+	"go.googlesource.com": 2,
+}
+
+// Name returns the name of the group containing the Node. Internal packages are
+// always grouped as GroupStd.
+func (by Grouping) Name(n *Node) string {
+	if n.Internal {
+		return GroupStd
+	}
+
+	switch by {
+	case GroupModule:
+		if n.Pkg != nil && n.Pkg.Module != nil {
+			return n.Pkg.Module.Path
+		}
+	case GroupDomain:
+		return strings.SplitN(n.Name, "/", 2)[0]
+	}
+
+	var module string
+	if n.Pkg != nil && n.Pkg.Module != nil {
+		module = n.Pkg.Module.Path
+	}
+	return repoRoot(n.Name, module)
+}
+
+// repoRoot returns the root of the repository hosting a package, given its import
+// path and the path of its module, if known. Outside of well-known hosts, the module
+// path without its major version suffix is used, falling back to the first three
+// elements of the import path.
+func repoRoot(importPath, module string) string {
+	parts := strings.Split(importPath, "/")
+	if n, ok := repoHosts[parts[0]]; ok && len(parts) >= n {
+		return strings.Join(parts[:n], "/")
+	}
+
+	if module != "" {
+		if i := strings.LastIndex(module, "/"); i > 0 && isMajorVersion(module[i+1:]) {
+			return module[:i]
+		}
+		return module
+	}
+
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+	return strings.Join(parts, "/")
+}
+
+// isMajorVersion returns true if the path element is a major version suffix, such
+// as "v2".
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' || s == "v0" || s == "v1" {
+		return false
+	}
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Group returns a new Graph with a single Node for each group of packages, named
+// by the Grouping provided, and a single Edge for the imports between each pair of
+// groups. Imports within a group are omitted.
+//
+// Each Node lists the Packages it collapses, and each Edge lists its Imports. A group
+// is Internal and Resolved if all of its packages are, and an Edge is only Test if
+// all of its imports are.
+func (g *Graph) Group(by Grouping) *Graph {
+	grouped := &Graph{
+		platforms: g.platforms,
+		nodes:     make(map[string]*Node),
+		imports:   make(map[string][]Edge),
+		importers: make(map[string][]Edge),
+	}
+
+	names := make(map[string]string)
+	for _, n := range g.Nodes() {
+		name := by.Name(n)
+		names[n.Name] = name
+
+		group, ok := grouped.nodes[name]
+		if !ok {
+			group = &Node{Name: name, Internal: true, Resolved: true}
+			grouped.nodes[name] = group
+		}
+		group.Internal = group.Internal && n.Internal
+		group.Resolved = group.Resolved && n.Resolved
+		group.Packages = append(group.Packages, n)
+	}
+	for _, n := range grouped.nodes {
+		n.Pkg = &Pkg{Name: n.Name, Internal: n.Internal, Resolved: n.Resolved}
+	}
+
+	for _, r := range g.roots {
+		if name := names[r]; !isRoot(grouped, name) {
+			grouped.roots = append(grouped.roots, name)
+		}
+	}
+
+	edges := make(map[[2]string]*Edge)
+	var order [][2]string
+	for _, e := range g.Edges() {
+		key := [2]string{names[e.From], names[e.To]}
+		if key[0] == key[1] {
+			continue
+		}
+
+		ge, ok := edges[key]
+		if !ok {
+			ge = &Edge{From: key[0], To: key[1], Test: true}
+			edges[key] = ge
+			order = append(order, key)
+		}
+		ge.Test = ge.Test && e.Test
+		ge.Platforms = unionPlatforms(g.platforms, ge.Platforms, e.Platforms)
+		ge.Imports = append(ge.Imports, e)
+	}
+
+	for _, key := range order {
+		e := *edges[key]
+		grouped.imports[e.From] = append(grouped.imports[e.From], e)
+		grouped.importers[e.To] = append(grouped.importers[e.To], e)
+	}
+	for from, imports := range grouped.imports {
+		sort.SliceStable(imports, func(i, j int) bool {
+			a, b := grouped.nodes[imports[i].To], grouped.nodes[imports[j].To]
+			if a.Internal != b.Internal {
+				return a.Internal
+			}
+			return a.Name < b.Name
+		})
+		grouped.imports[from] = imports
+	}
+	grouped.markTest()
+
+	return grouped
+}
+
+// unionPlatforms returns the platforms present in either a or b, in the order of all.
+func unionPlatforms(all, a, b []string) []string {
+	if len(all) == 0 {
+		return nil
+	}
+
+	present := make(map[string]bool)
+	for _, p := range append(append([]string{}, a...), b...) {
+		present[p] = true
+	}
+
+	var union []string
+	for _, p := range all {
+		if present[p] {
+			union = append(union, p)
+		}
+	}
+	return union
+}
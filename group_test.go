@@ -0,0 +1,85 @@
+package depth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraph_Group(t *testing.T) {
+	tr := Tree{
+		ResolveTest: true,
+		Importer: mockGraphImporter(map[string][]string{
+			"github.com/foo/app":         {"github.com/foo/app/store", "github.com/x/lib/a", "github.com/x/lib/b"},
+			"github.com/foo/app_test":    {"gopkg.in/check.v1"},
+			"github.com/foo/app/store":   {"github.com/x/lib/a"},
+			"github.com/x/lib/a":         {"github.com/x/lib/b"},
+			"github.com/x/lib/b":         {},
+			"gopkg.in/check.v1":          {"gopkg.in/check.v1/internal"},
+			"gopkg.in/check.v1/internal": {},
+		}),
+	}
+	if err := tr.Resolve("github.com/foo/app"); err != nil {
+		t.Fatal(err)
+	}
+
+	g := tr.Graph.Group(GroupRepo)
+	if roots := nodeNames(g.Roots()); !reflect.DeepEqual(roots, []string{"github.com/foo/app"}) {
+		t.Fatalf("Unexpected Roots, got=%v", roots)
+	}
+	if nodes := nodeNames(g.Nodes()); !reflect.DeepEqual(nodes, []string{"github.com/foo/app", "github.com/x/lib", "gopkg.in/check.v1"}) {
+		t.Fatalf("Unexpected Nodes, got=%v", nodes)
+	}
+
+	if pkgs := nodeNames(g.Node("github.com/x/lib").Packages); !reflect.DeepEqual(pkgs, []string{"github.com/x/lib/a", "github.com/x/lib/b"}) {
+		t.Fatalf("Unexpected Packages, got=%v", pkgs)
+	}
+	if !g.Node("gopkg.in/check.v1").Test || g.Node("github.com/x/lib").Test {
+		t.Fatal("Expected only gopkg.in/check.v1 to be a Test group")
+	}
+
+	edges := g.EdgesFrom("github.com/foo/app")
+	if len(edges) != 2 {
+		t.Fatalf("Unexpected number of Edges, expected=%v, got=%+v", 2, edges)
+	}
+	if e := edges[0]; e.To != "github.com/x/lib" || e.Test || len(e.Imports) != 3 {
+		t.Fatalf("Unexpected Edge, expected 3 imports of github.com/x/lib, got=%+v", e)
+	}
+	if e := edges[1]; e.To != "gopkg.in/check.v1" || !e.Test || len(e.Imports) != 1 {
+		t.Fatalf("Unexpected Edge, expected 1 test import of gopkg.in/check.v1, got=%+v", e)
+	}
+	if s := g.Summary(); s != (Summary{External: 2, Testing: 1}) {
+		t.Fatalf("Unexpected Summary, got=%+v", s)
+	}
+
+	if nodes := nodeNames(tr.Graph.Group(GroupDomain).Nodes()); !reflect.DeepEqual(nodes, []string{"github.com", "gopkg.in"}) {
+		t.Fatalf("Unexpected domain Nodes, got=%v", nodes)
+	}
+}
+
+func TestGrouping_Name(t *testing.T) {
+	tests := []struct {
+		by       Grouping
+		name     string
+		module   *Module
+		internal bool
+		expected string
+	}{
+		{GroupModule, "strings", nil, true, GroupStd},
+		{GroupModule, "example.com/lib/v2/util", &Module{Path: "example.com/lib/v2"}, false, "example.com/lib/v2"},
+		{GroupModule, "github.com/foo/bar/a", nil, false, "github.com/foo/bar"},
+		{GroupRepo, "example.com/lib/v2/util", &Module{Path: "example.com/lib/v2"}, false, "example.com/lib"},
+		{GroupRepo, "github.com/foo/bar/sub/a", &Module{Path: "github.com/foo/bar/sub"}, false, "github.com/foo/bar"},
+		{GroupRepo, "golang.org/x/tools/go/packages", nil, false, "golang.org/x/tools"},
+		{GroupRepo, "gopkg.in/yaml.v3", nil, false, "gopkg.in/yaml.v3"},
+		{GroupRepo, "example.com/a/b/c/d", nil, false, "example.com/a/b"},
+		{GroupDomain, "github.com/foo/bar/a", nil, false, "github.com"},
+		{GroupDomain, "unicode/utf8", nil, true, GroupStd},
+	}
+
+	for idx, tt := range tests {
+		n := &Node{Name: tt.name, Internal: tt.internal, Pkg: &Pkg{Name: tt.name, Module: tt.module}}
+		if name := tt.by.Name(n); name != tt.expected {
+			t.Fatalf("[%v] Unexpected group of %v by %v, expected=%v, got=%v", idx, tt.name, tt.by, tt.expected, name)
+		}
+	}
+}
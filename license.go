@@ -0,0 +1,243 @@
+package depth
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// LicenseUnknown is the SPDX identifier used for license files that can't be
+	// classified.
+	LicenseUnknown = "Unknown"
+
+	// LicenseNone is the SPDX identifier used when no license file is found.
+	LicenseNone = "None"
+)
+
+// License is the license of an external package.
+type License struct {
+	Package string `json:"package"`
+
+	// Module is the module providing the package, if known.
+	Module *Module `json:"module,omitempty"`
+
+	// SPDX is the SPDX identifier of the license, LicenseUnknown if the license file
+	// can't be classified, or LicenseNone if there is no license file.
+	SPDX string `json:"spdx"`
+
+	// File is the path of the license file, if any.
+	File string `json:"file,omitempty"`
+}
+
+// licensePattern identifies a license by phrases that appear in its normalized text.
+// Every phrase of all must appear, and none of those of none.
+type licensePattern struct {
+	spdx string
+	all  []string
+	none []string
+}
+
+// licensePatterns are the licenses that can be classified, in the order they are
+// tried, so that more specific licenses come before those whose phrases they share.
+var licensePatterns = []licensePattern{
+	{spdx: "AGPL-3.0", all: []string{"gnu affero general public license version 3"}},
+	{spdx: "LGPL-3.0", all: []string{"gnu lesser general public license version 3"}},
+	{spdx: "LGPL-2.1", all: []string{"gnu lesser general public license version 2 1"}},
+	{spdx: "GPL-3.0", all: []string{"gnu general public license version 3"}},
+	{spdx: "GPL-2.0", all: []string{"gnu general public license version 2"}},
+	{spdx: "MPL-2.0", all: []string{"mozilla public license version 2 0"}},
+	{spdx: "Apache-2.0", all: []string{"apache license version 2 0"}},
+	{spdx: "BSL-1.0", all: []string{"boost software license version 1 0"}},
+	{spdx: "CC0-1.0", all: []string{"cc0 1 0 universal"}},
+	{spdx: "Unlicense", all: []string{"this is free and unencumbered software released into the public domain"}},
+	{spdx: "ISC", all: []string{"permission to use copy modify and or distribute this software for any purpose with or without fee is hereby granted"}},
+	{spdx: "MIT", all: []string{"permission is hereby granted free of charge to any person obtaining a copy"}},
+	{spdx: "BSD-3-Clause", all: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{spdx: "BSD-3-Clause", all: []string{"redistribution and use in source and binary forms", "names of its contributors may not be used"}},
+	{spdx: "BSD-2-Clause", all: []string{"redistribution and use in source and binary forms"}, none: []string{"neither the name", "may not be used to endorse"}},
+	{spdx: "Zlib", all: []string{"this software is provided as is without any express or implied warranty", "must not be misrepresented"}},
+}
+
+// Licenses returns the License of every external package in the Graph, ordered by
+// package name. The roots of the Graph, and the packages of the main module when
+// modules are known, are not included.
+//
+// Each package is licensed by the nearest license file, such as LICENSE or COPYING,
+// in its directory or a parent directory up to the root of its module. Modules are
+// known when resolved by a ModuleImporter, otherwise the root is the nearest parent
+// directory containing a go.mod file.
+func (g *Graph) Licenses() []License {
+	var licenses []License
+	files := make(map[string]string)
+	for _, n := range g.Nodes() {
+		if n.Internal || isRoot(g, n.Name) || (n.Pkg.Module != nil && n.Pkg.Module.Main) {
+			continue
+		}
+
+		l := License{Package: n.Name, Module: n.Pkg.Module, SPDX: LicenseNone}
+		if n.Pkg.Raw != nil && n.Pkg.Raw.Dir != "" {
+			l.File = findLicense(n.Pkg.Raw.Dir, moduleRoot(n.Pkg.Module, n.Pkg.Raw.Dir))
+		}
+		if l.File != "" {
+			spdx, ok := files[l.File]
+			if !ok {
+				spdx = classifyLicenseFile(l.File)
+				files[l.File] = spdx
+			}
+			l.SPDX = spdx
+		}
+
+		licenses = append(licenses, l)
+	}
+
+	sort.Slice(licenses, func(i, j int) bool {
+		return licenses[i].Package < licenses[j].Package
+	})
+	return licenses
+}
+
+// moduleRoot returns the root directory of the Module providing the package in the
+// directory provided, if known. Vendored modules don't have a directory, so their
+// root is found within the vendor directory.
+func moduleRoot(m *Module, dir string) string {
+	if m == nil {
+		return ""
+	}
+	if m.Replace != nil && m.Replace.Dir != "" {
+		return m.Replace.Dir
+	}
+	if m.Dir != "" {
+		return m.Dir
+	}
+
+	vendored := string(filepath.Separator) + filepath.Join("vendor", filepath.FromSlash(m.Path))
+	if i := strings.LastIndex(dir, vendored); i >= 0 {
+		if end := i + len(vendored); end == len(dir) || dir[end] == filepath.Separator {
+			return dir[:end]
+		}
+	}
+	return ""
+}
+
+// findLicense returns the nearest license file in the directory or its parents, up to
+// the root directory provided or, if none is, the nearest directory containing a
+// go.mod file.
+func findLicense(dir, root string) string {
+	for {
+		if f := licenseFile(dir); f != "" {
+			return f
+		}
+
+		if dir == root {
+			return ""
+		}
+		if root == "" {
+			if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+				return ""
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// licenseFile returns the license file in a directory, if any, preferring files named
+// LICENSE or LICENCE over COPYING. Each name matches regardless of case, either
+// without an extension or with a .txt or .md extension, so source files such as
+// license.go are never mistaken for license files.
+func licenseFile(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	var found string
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		name := strings.ToLower(e.Name())
+		switch ext := filepath.Ext(name); ext {
+		case ".txt", ".md":
+			name = strings.TrimSuffix(name, ext)
+		case "":
+		default:
+			continue
+		}
+
+		switch {
+		case name == "license", name == "licence":
+			return filepath.Join(dir, e.Name())
+		case name == "copying" && found == "":
+			found = filepath.Join(dir, e.Name())
+		}
+	}
+	return found
+}
+
+// classifyLicenseFile returns the SPDX identifier of the license file.
+func classifyLicenseFile(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return LicenseUnknown
+	}
+	return ClassifyLicense(string(b))
+}
+
+// ClassifyLicense returns the SPDX identifier of a license text, or LicenseUnknown if
+// it can't be classified. An SPDX-License-Identifier line takes precedence over the
+// text.
+func ClassifyLicense(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.Index(line, "SPDX-License-Identifier:"); i >= 0 {
+			if id := strings.TrimSpace(line[i+len("SPDX-License-Identifier:"):]); id != "" {
+				return id
+			}
+		}
+	}
+
+	normalized := normalizeLicense(text)
+	for _, p := range licensePatterns {
+		if containsAll(normalized, p.all) && !containsAny(normalized, p.none) {
+			return p.spdx
+		}
+	}
+	return LicenseUnknown
+}
+
+// normalizeLicense lowercases a license text and replaces punctuation and runs of
+// whitespace with single spaces, so that phrases match regardless of formatting.
+func normalizeLicense(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return " " + strings.Join(fields, " ") + " "
+}
+
+// containsAll returns true if the normalized text contains every phrase.
+func containsAll(text string, phrases []string) bool {
+	for _, p := range phrases {
+		if !strings.Contains(text, " "+p+" ") {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAny returns true if the normalized text contains any of the phrases.
+func containsAny(text string, phrases []string) bool {
+	for _, p := range phrases {
+		if strings.Contains(text, " "+p+" ") {
+			return true
+		}
+	}
+	return false
+}
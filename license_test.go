@@ -0,0 +1,182 @@
+package depth
+
+import (
+	"go/build"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	testMIT = `MIT License
+
+Copyright (c) 2017 Foo
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction.`
+
+	testBSD3 = `Copyright (c) 2009 The Foo Authors. All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+   * Neither the name of Foo nor the names of its contributors may be used to
+endorse or promote products derived from this software.`
+)
+
+func TestClassifyLicense(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{testMIT, "MIT"},
+		{testBSD3, "BSD-3-Clause"},
+		{"Redistribution and use in source and binary forms, with or without\nmodification, are permitted.", "BSD-2-Clause"},
+		{"\n                                 Apache License\n                           Version 2.0, January 2004\n", "Apache-2.0"},
+		{"GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\n... use the GNU Lesser General Public License instead of this License.", "GPL-3.0"},
+		{"GNU LESSER GENERAL PUBLIC LICENSE\nVersion 2.1, February 1999", "LGPL-2.1"},
+		{"GNU GENERAL PUBLIC LICENSE\nVersion 2, June 1991", "GPL-2.0"},
+		{"Mozilla Public License Version 2.0\n==================================", "MPL-2.0"},
+		{"ISC License\n\nPermission to use, copy, modify, and/or distribute this software for any\npurpose with or without fee is hereby granted.", "ISC"},
+		{"This is free and unencumbered software released into the public domain.", "Unlicense"},
+		{"// SPDX-License-Identifier: EPL-2.0\n" + testMIT, "EPL-2.0"},
+		{"All rights reserved.", LicenseUnknown},
+	}
+
+	for idx, tt := range tests {
+		if got := ClassifyLicense(tt.text); got != tt.expected {
+			t.Fatalf("[%v] Unexpected license, expected=%v, got=%v", idx, tt.expected, got)
+		}
+	}
+}
+
+func Test_findLicense(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"LICENSE.txt":             testMIT,
+		"mod/go.mod":              "module mod\n",
+		"mod/a/b/b.go":            "package b\n",
+		"mod/COPYING":             testBSD3,
+		"mod/LICENSE":             testBSD3,
+		"nolicense/go.mod":        "module nolicense\n",
+		"nolicense/a/a.go":        "package a\n",
+		"copying/COPYING":         testBSD3,
+		"copying/README":          "",
+		"modcache/mod@v1/LICENSE": testMIT,
+		"modcache/mod@v1/a/a.go":  "package a\n",
+		"source/go.mod":           "module source\n",
+		"source/LICENSE.md":       testMIT,
+		"source/a/license.go":     "package a\n",
+		"source/a/licenses.txt":   testBSD3,
+	})
+
+	tests := []struct {
+		dir, root string
+		expected  string
+	}{
+		{"mod/a/b", "", "mod/LICENSE"},
+		{"nolicense/a", "", ""},
+		{"copying", "", "copying/COPYING"},
+		{"modcache/mod@v1/a", "modcache/mod@v1", "modcache/mod@v1/LICENSE"},
+		{"nolicense/a", "nolicense/a", ""},
+		{"source/a", "", "source/LICENSE.md"},
+	}
+
+	for idx, tt := range tests {
+		root := ""
+		if tt.root != "" {
+			root = filepath.Join(dir, tt.root)
+		}
+		expected := ""
+		if tt.expected != "" {
+			expected = filepath.Join(dir, tt.expected)
+		}
+
+		if got := findLicense(filepath.Join(dir, tt.dir), root); got != expected {
+			t.Fatalf("[%v] Unexpected license file, expected=%v, got=%v", idx, expected, got)
+		}
+	}
+}
+
+func TestGraph_Licenses(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/go.mod":         "module app\n",
+		"app/lib/lib.go":     "package lib\n",
+		"mit@v1/LICENSE":     testMIT,
+		"mit@v1/a/a.go":      "package a\n",
+		"mit@v1/b/b.go":      "package b\n",
+		"bsd@v1/COPYING":     testBSD3,
+		"none@v1/none.go":    "package none\n",
+		"unknown@v1/LICENSE": "All rights reserved.",
+	})
+
+	dirs := map[string]string{
+		"app":     "app",
+		"app/lib": "app/lib",
+		"mit/a":   "mit@v1/a",
+		"mit/b":   "mit@v1/b",
+		"bsd":     "bsd@v1",
+		"none":    "none@v1",
+		"unknown": "unknown@v1",
+	}
+	modules := map[string]*Module{
+		"app":     {Path: "app", Main: true, Dir: filepath.Join(dir, "app")},
+		"app/lib": {Path: "app", Main: true, Dir: filepath.Join(dir, "app")},
+		"mit/a":   {Path: "mit", Version: "v1.0.0", Dir: filepath.Join(dir, "mit@v1")},
+		"mit/b":   {Path: "mit", Version: "v1.0.0", Dir: filepath.Join(dir, "mit@v1")},
+		"bsd":     {Path: "bsd", Version: "v1.0.0", Dir: filepath.Join(dir, "bsd@v1")},
+		"none":    {Path: "none", Version: "v1.0.0", Dir: filepath.Join(dir, "none@v1")},
+		"unknown": {Path: "unknown", Version: "v1.0.0", Dir: filepath.Join(dir, "unknown@v1")},
+	}
+
+	tr := Tree{
+		Importer: mockModuleImporter{
+			MockImporter: MockImporter{
+				ImportFn: func(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+					pkg := &build.Package{ImportPath: name, Name: name, Dir: filepath.Join(dir, dirs[name]), Goroot: name == "fmt"}
+					if name == "app" {
+						pkg.Imports = []string{"app/lib", "bsd", "fmt", "mit/a", "mit/b", "none", "unknown"}
+					}
+					return pkg, nil
+				},
+			},
+			modules: modules,
+		},
+	}
+	if err := tr.Resolve("app"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []License{
+		{Package: "bsd", Module: modules["bsd"], SPDX: "BSD-3-Clause", File: filepath.Join(dir, "bsd@v1/COPYING")},
+		{Package: "mit/a", Module: modules["mit/a"], SPDX: "MIT", File: filepath.Join(dir, "mit@v1/LICENSE")},
+		{Package: "mit/b", Module: modules["mit/b"], SPDX: "MIT", File: filepath.Join(dir, "mit@v1/LICENSE")},
+		{Package: "none", Module: modules["none"], SPDX: LicenseNone},
+		{Package: "unknown", Module: modules["unknown"], SPDX: LicenseUnknown, File: filepath.Join(dir, "unknown@v1/LICENSE")},
+	}
+	if got := tr.Graph.Licenses(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Unexpected Licenses, expected=%+v, got=%+v", expected, got)
+	}
+}
+
+func Test_moduleRoot(t *testing.T) {
+	dir := filepath.FromSlash("/app/vendor/example.com/lib/sub")
+	tests := []struct {
+		m        *Module
+		expected string
+	}{
+		{nil, ""},
+		{&Module{Path: "example.com/lib", Dir: "/mod/lib"}, "/mod/lib"},
+		{&Module{Path: "example.com/lib", Dir: "/mod/lib", Replace: &Module{Path: "../lib", Dir: "/lib"}}, "/lib"},
+		{&Module{Path: "example.com/lib"}, "/app/vendor/example.com/lib"},
+		{&Module{Path: "example.com/li"}, ""},
+		{&Module{Path: "example.com/other"}, ""},
+	}
+
+	for idx, tt := range tests {
+		if got := moduleRoot(tt.m, dir); got != filepath.FromSlash(tt.expected) {
+			t.Fatalf("[%v] Unexpected root, expected=%v, got=%v", idx, tt.expected, got)
+		}
+	}
+}
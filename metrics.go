@@ -0,0 +1,128 @@
+package depth
+
+// Metrics contains the coupling metrics of a package within a Graph.
+type Metrics struct {
+	Name     string `json:"name"`
+	Internal bool   `json:"internal"`
+
+	// Afferent coupling (Ca) counts the packages that directly import the package.
+	Afferent int `json:"afferent"`
+	// Efferent coupling (Ce) counts the packages directly imported by the package.
+	Efferent int `json:"efferent"`
+	// Instability is Ce / (Ca + Ce), ranging from 0 for a package that only others
+	// depend on, to 1 for a package that only depends on others. Packages without
+	// any imports or importers have an Instability of 0.
+	Instability float64 `json:"instability"`
+
+	// FanIn and FanOut count the packages that import the package, and that it
+	// imports, directly or transitively.
+	FanIn  int `json:"fan_in"`
+	FanOut int `json:"fan_out"`
+
+	// Depth is the fewest imports between the package and a root of the Graph that
+	// isn't imported by another package, such as a main package among the roots
+	// matched by "./...". Roots that can't be reached from those, such as roots
+	// within a cycle, are counted from in turn.
+	Depth int `json:"depth"`
+}
+
+// Metrics returns the coupling Metrics of every Node in the Graph, in the same order
+// as Nodes.
+//
+// Every import within the Graph is counted, including test imports when the Tree
+// is resolved with ResolveTest.
+//
+// The packages of an import cycle share their FanIn and FanOut, which are counted
+// once for each strongly connected component of the Graph. This still takes
+// O(C·(N+E)) time for C components, so Metrics is slow on very large Graphs.
+func (g *Graph) Metrics() []Metrics {
+	depths := g.depths()
+
+	fanIn, fanOut := make(map[string]int), make(map[string]int)
+	for _, members := range g.components(true) {
+		in := g.reach(members[0], func(e Edge) string { return e.From }, g.importers)
+		out := g.reach(members[0], func(e Edge) string { return e.To }, g.imports)
+		for _, name := range members {
+			fanIn[name], fanOut[name] = in, out
+		}
+	}
+
+	var metrics []Metrics
+	for _, n := range g.Nodes() {
+		m := Metrics{
+			Name:     n.Name,
+			Internal: n.Internal,
+			Afferent: len(g.importers[n.Name]),
+			Efferent: len(g.imports[n.Name]),
+			FanIn:    fanIn[n.Name],
+			FanOut:   fanOut[n.Name],
+			Depth:    depths[n.Name],
+		}
+		if total := m.Afferent + m.Efferent; total > 0 {
+			m.Instability = float64(m.Efferent) / float64(total)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+// depths returns the fewest imports between the roots of the Graph that aren't
+// imported by another package and each package. The roots that aren't reached from
+// those, such as roots within a cycle, are then counted from in turn.
+func (g *Graph) depths() map[string]int {
+	depths := make(map[string]int)
+	walk := func(queue []string) {
+		for _, r := range queue {
+			depths[r] = 0
+		}
+
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+
+			for _, e := range g.imports[name] {
+				if _, ok := depths[e.To]; !ok {
+					depths[e.To] = depths[name] + 1
+					queue = append(queue, e.To)
+				}
+			}
+		}
+	}
+
+	var seeds []string
+	for _, r := range g.roots {
+		if len(g.importers[r]) == 0 {
+			seeds = append(seeds, r)
+		}
+	}
+	walk(seeds)
+
+	for _, r := range g.roots {
+		if _, ok := depths[r]; !ok {
+			walk([]string{r})
+		}
+	}
+	return depths
+}
+
+// reach counts the packages reached from the package provided by following the
+// Edges of adjacent, such as g.imports or g.importers, to the package that next
+// returns for each.
+func (g *Graph) reach(name string, next func(Edge) string, adjacent map[string][]Edge) int {
+	seen := map[string]struct{}{name: {}}
+	queue := []string{name}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		for _, e := range adjacent[n] {
+			to := next(e)
+			if _, ok := seen[to]; ok {
+				continue
+			}
+			seen[to] = struct{}{}
+			queue = append(queue, to)
+		}
+	}
+	return len(seen) - 1
+}
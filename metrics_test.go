@@ -0,0 +1,62 @@
+package depth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraph_Metrics(t *testing.T) {
+	tr := Tree{
+		ResolveTest: true,
+		Importer: mockGraphImporter(map[string][]string{
+			"a":      {"b", "c"},
+			"a_test": {"e"},
+			"b":      {"d"},
+			"c":      {"d"},
+			"d":      {},
+			"e":      {"d", "f"},
+			"f":      {},
+		}),
+	}
+	if err := tr.Resolve("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Metrics{
+		{Name: "a", Afferent: 0, Efferent: 3, Instability: 1, FanIn: 0, FanOut: 5, Depth: 0},
+		{Name: "b", Afferent: 1, Efferent: 1, Instability: 0.5, FanIn: 1, FanOut: 1, Depth: 1},
+		{Name: "c", Afferent: 1, Efferent: 1, Instability: 0.5, FanIn: 1, FanOut: 1, Depth: 1},
+		{Name: "d", Afferent: 3, Efferent: 0, Instability: 0, FanIn: 4, FanOut: 0, Depth: 2},
+		{Name: "e", Afferent: 1, Efferent: 2, Instability: 2.0 / 3, FanIn: 1, FanOut: 2, Depth: 1},
+		{Name: "f", Afferent: 1, Efferent: 0, Instability: 0, FanIn: 2, FanOut: 0, Depth: 2},
+	}
+	if m := tr.Graph.Metrics(); !reflect.DeepEqual(m, expected) {
+		t.Fatalf("Unexpected Metrics, expected=%+v, got=%+v", expected, m)
+	}
+}
+
+func TestGraph_MetricsCycles(t *testing.T) {
+	tr := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"a": {"b"},
+			"b": {},
+			"c": {"d"},
+			"d": {"c", "e"},
+			"e": {},
+		}),
+	}
+	if err := tr.resolve([]string{"a", "c", "d"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Metrics{
+		{Name: "a", Afferent: 0, Efferent: 1, Instability: 1, FanIn: 0, FanOut: 1, Depth: 0},
+		{Name: "b", Afferent: 1, Efferent: 0, Instability: 0, FanIn: 1, FanOut: 0, Depth: 1},
+		{Name: "c", Afferent: 1, Efferent: 1, Instability: 0.5, FanIn: 1, FanOut: 2, Depth: 0},
+		{Name: "d", Afferent: 1, Efferent: 2, Instability: 2.0 / 3, FanIn: 1, FanOut: 2, Depth: 1},
+		{Name: "e", Afferent: 1, Efferent: 0, Instability: 0, FanIn: 2, FanOut: 0, Depth: 2},
+	}
+	if m := tr.Graph.Metrics(); !reflect.DeepEqual(m, expected) {
+		t.Fatalf("Unexpected Metrics, expected=%+v, got=%+v", expected, m)
+	}
+}
@@ -0,0 +1,109 @@
+package depth
+
+// ShortestPath returns the shortest chain of imports from one package to another,
+// including both packages, or nil if the target is not reachable.
+//
+// When several chains are equally short, the first in import order is returned.
+func (g *Graph) ShortestPath(from, to string) []string {
+	if _, ok := g.nodes[from]; !ok {
+		return nil
+	}
+
+	parents := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if name == to {
+			var path []string
+			for n := to; n != ""; n = parents[n] {
+				path = append([]string{n}, path...)
+			}
+			return path
+		}
+
+		for _, e := range g.imports[name] {
+			if _, ok := parents[e.To]; ok {
+				continue
+			}
+			parents[e.To] = name
+			queue = append(queue, e.To)
+		}
+	}
+
+	return nil
+}
+
+// RootPath returns the shortest chain of imports from any root of the Graph to the
+// package provided, or nil if it is not reachable from the roots.
+func (g *Graph) RootPath(name string) []string {
+	var shortest []string
+	for _, r := range g.roots {
+		if p := g.ShortestPath(r, name); p != nil && (shortest == nil || len(p) < len(shortest)) {
+			shortest = p
+		}
+	}
+	return shortest
+}
+
+// AllPaths returns every simple chain of imports from one package to another, in
+// import order. Cycles are never followed, so no package appears twice in a chain.
+//
+// The number of chains can grow exponentially with the size of the Graph, so max
+// should usually be positive, in which case at most max chains are returned.
+func (g *Graph) AllPaths(from, to string, max int) [][]string {
+	if _, ok := g.nodes[from]; !ok {
+		return nil
+	}
+
+	// Only packages that reach the target are walked, so every branch of the walk
+	// leads to a chain and a positive max bounds the work done.
+	reaches := map[string]struct{}{to: {}}
+	queue := []string{to}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.importers[name] {
+			if _, ok := reaches[e.From]; !ok {
+				reaches[e.From] = struct{}{}
+				queue = append(queue, e.From)
+			}
+		}
+	}
+	if _, ok := reaches[from]; !ok {
+		return nil
+	}
+
+	var paths [][]string
+	onPath := make(map[string]struct{})
+
+	var walk func(stack []string) bool
+	walk = func(stack []string) bool {
+		name := stack[len(stack)-1]
+		if name == to {
+			paths = append(paths, append([]string{}, stack...))
+			return max <= 0 || len(paths) < max
+		}
+
+		onPath[name] = struct{}{}
+		defer delete(onPath, name)
+
+		for _, e := range g.imports[name] {
+			if _, ok := onPath[e.To]; ok {
+				continue
+			}
+			if _, ok := reaches[e.To]; !ok {
+				continue
+			}
+			if !walk(append(stack, e.To)) {
+				return false
+			}
+		}
+		return true
+	}
+	walk([]string{from})
+
+	return paths
+}
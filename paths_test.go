@@ -0,0 +1,117 @@
+package depth
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// pathsGraph returns a Graph containing a cycle, several paths from "a" to "e",
+// and a duplicate import of "d" that a Pkg tree only resolves once.
+func pathsGraph(t *testing.T) *Graph {
+	tr := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"a": {"b", "c", "d"},
+			"b": {"d"},
+			"c": {"b", "e"},
+			"d": {"a", "e"},
+			"e": {},
+			"f": {},
+		}),
+	}
+	if err := tr.Resolve("a"); err != nil {
+		t.Fatal(err)
+	}
+	return tr.Graph
+}
+
+func TestGraph_ShortestPath(t *testing.T) {
+	g := pathsGraph(t)
+
+	tests := []struct {
+		from, to string
+		expected []string
+	}{
+		{"a", "e", []string{"a", "c", "e"}},
+		{"a", "a", []string{"a"}},
+		{"b", "e", []string{"b", "d", "e"}},
+		{"d", "c", []string{"d", "a", "c"}},
+		{"e", "a", nil},
+		{"a", "f", nil},
+		{"f", "a", nil},
+	}
+
+	for idx, tt := range tests {
+		if out := g.ShortestPath(tt.from, tt.to); !reflect.DeepEqual(out, tt.expected) {
+			t.Fatalf("[%v] Unexpected ShortestPath, expected=%v, got=%v", idx, tt.expected, out)
+		}
+	}
+}
+
+func TestGraph_RootPath(t *testing.T) {
+	g := pathsGraph(t)
+
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"a", []string{"a"}},
+		{"e", []string{"a", "c", "e"}},
+		{"d", []string{"a", "d"}},
+		{"f", nil},
+	}
+
+	for idx, tt := range tests {
+		if out := g.RootPath(tt.name); !reflect.DeepEqual(out, tt.expected) {
+			t.Fatalf("[%v] Unexpected RootPath, expected=%v, got=%v", idx, tt.expected, out)
+		}
+	}
+}
+
+func TestGraph_AllPaths(t *testing.T) {
+	g := pathsGraph(t)
+
+	expected := [][]string{
+		{"a", "b", "d", "e"},
+		{"a", "c", "b", "d", "e"},
+		{"a", "c", "e"},
+		{"a", "d", "e"},
+	}
+	if out := g.AllPaths("a", "e", 0); !reflect.DeepEqual(out, expected) {
+		t.Fatalf("Unexpected AllPaths, expected=%v, got=%v", expected, out)
+	}
+
+	if out := g.AllPaths("a", "e", 2); !reflect.DeepEqual(out, expected[:2]) {
+		t.Fatalf("Unexpected capped AllPaths, expected=%v, got=%v", expected[:2], out)
+	}
+
+	if out := g.AllPaths("e", "a", 0); out != nil {
+		t.Fatalf("Unexpected AllPaths, expected=nil, got=%v", out)
+	}
+}
+
+func TestGraph_AllPathsDiamonds(t *testing.T) {
+	// A chain of diamonds has 2^40 paths to its end, none of which reach the target,
+	// and must not be walked.
+	imports := map[string][]string{
+		"root":   {"target", "x0"},
+		"target": {},
+		"x40":    {},
+	}
+	for i := 0; i < 40; i++ {
+		x, y, z, next := fmt.Sprintf("x%d", i), fmt.Sprintf("y%d", i), fmt.Sprintf("z%d", i), fmt.Sprintf("x%d", i+1)
+		imports[x] = []string{y, z}
+		imports[y] = []string{next}
+		imports[z] = []string{next}
+	}
+
+	tr := Tree{Importer: mockGraphImporter(imports)}
+	if err := tr.Resolve("root"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{{"root", "target"}}
+	if out := tr.Graph.AllPaths("root", "target", 0); !reflect.DeepEqual(out, expected) {
+		t.Fatalf("Unexpected AllPaths, expected=%v, got=%v", expected, out)
+	}
+}
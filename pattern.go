@@ -0,0 +1,204 @@
+package depth
+
+import (
+	"errors"
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidPattern is returned when a package pattern cannot be expanded.
+var ErrInvalidPattern = errors.New("invalid package pattern")
+
+// ErrNoPackages is returned when package patterns don't match any packages.
+var ErrNoPackages = errors.New("no packages match the patterns")
+
+// Expand returns the names of all packages matching the patterns provided, relative
+// to the Dir of the Tree.
+//
+// Local packages within a module are returned as fully qualified import paths. As
+// with the go command, the "..." wildcard matches any string, including the empty
+// string and strings containing slashes, so "./..." matches every package in or
+// below the directory and "net/..." matches net and its subpackages. The testdata
+// and vendor directories, directories beginning with "." or "_", and nested modules
+// are never matched. Other patterns without a wildcard are returned as-is.
+func (t *Tree) Expand(patterns ...string) ([]string, error) {
+	pwd, err := t.srcDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	seen := make(map[string]struct{})
+	for _, pattern := range patterns {
+		matches := []string{pattern}
+		if strings.Contains(pattern, "...") {
+			if matches, err = t.expandPattern(pattern, pwd); err != nil {
+				return nil, err
+			}
+		} else if build.IsLocalImport(pattern) || filepath.IsAbs(pattern) {
+			dir := pattern
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(pwd, dir)
+			}
+			if name := localImportPath(dir); name != "" {
+				matches[0] = name
+			}
+		}
+
+		for _, m := range matches {
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			names = append(names, m)
+		}
+	}
+
+	return names, nil
+}
+
+// expandPattern walks the directory of the literal prefix of a wildcard pattern, and
+// returns the name of each package matching the pattern.
+func (t *Tree) expandPattern(pattern, srcDir string) ([]string, error) {
+	prefix := pattern[:strings.Index(pattern, "...")]
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		prefix = prefix[:i]
+	} else if !build.IsLocalImport(pattern) {
+		return nil, ErrInvalidPattern
+	} else {
+		prefix = "."
+	}
+
+	local := build.IsLocalImport(prefix) || filepath.IsAbs(prefix)
+	dir := prefix
+	if !local {
+		pkg, err := t.importer().Import(prefix, srcDir, build.FindOnly)
+		if err != nil {
+			return nil, err
+		}
+		dir = pkg.Dir
+	} else if !filepath.IsAbs(dir) {
+		dir = filepath.Join(srcDir, dir)
+	}
+
+	match := matchPattern(pattern)
+	var names []string
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if !info.IsDir() {
+			return nil
+		}
+
+		if p != dir {
+			base := info.Name()
+			if base == "testdata" || base == "vendor" || strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(prefix, filepath.ToSlash(rel))
+		if build.IsLocalImport(prefix) && !build.IsLocalImport(name) {
+			name = "./" + name
+		}
+
+		if !match(name) || !hasGoFiles(p) {
+			return nil
+		}
+
+		// Local packages within a module are named by their import path, as the
+		// go command would.
+		if local {
+			if importPath := localImportPath(p); importPath != "" {
+				name = importPath
+			}
+		}
+		names = append(names, name)
+		return nil
+	})
+
+	return names, err
+}
+
+// localImportPath returns the import path of the package in the directory provided,
+// or an empty string if it isn't within a module.
+func localImportPath(dir string) string {
+	modDir, modPath := findModule(dir)
+	if modPath == "" {
+		return ""
+	}
+
+	rel, err := filepath.Rel(modDir, dir)
+	if err != nil {
+		return ""
+	}
+	return path.Join(modPath, filepath.ToSlash(rel))
+}
+
+// findModule returns the root directory and path of the module containing the
+// directory provided, or empty strings if it isn't within a module.
+func findModule(dir string) (string, string) {
+	for {
+		if b, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, modulePath(b)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// modulePath returns the module path declared by the contents of a go.mod file.
+func modulePath(gomod []byte) string {
+	for _, line := range strings.Split(string(gomod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`+"`")
+		}
+	}
+	return ""
+}
+
+// matchPattern returns a function that reports whether a package name matches the
+// pattern provided.
+func matchPattern(pattern string) func(name string) bool {
+	re := regexp.QuoteMeta(pattern)
+	re = strings.Replace(re, `\.\.\.`, `.*`, -1)
+
+	// A trailing /... also matches the package itself, ie. net/... matches net.
+	if strings.HasSuffix(re, `/.*`) {
+		re = strings.TrimSuffix(re, `/.*`) + `(/.*)?`
+	}
+
+	reg := regexp.MustCompile(`^` + re + `$`)
+	return reg.MatchString
+}
+
+// hasGoFiles returns true if the directory provided contains any Go source files.
+func hasGoFiles(dir string) bool {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".go") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package depth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTree_Expand(t *testing.T) {
+	var tr Tree
+
+	tests := []struct {
+		patterns []string
+		expected []string
+	}{
+		{[]string{"strings"}, []string{"strings"}},
+		{[]string{"./..."}, []string{"github.com/KyleBanks/depth", "github.com/KyleBanks/depth/cmd/depth"}},
+		{[]string{"./cmd/..."}, []string{"github.com/KyleBanks/depth/cmd/depth"}},
+		{[]string{"./cmd/depth/testdata/rdeps/..."}, []string{
+			"github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/a",
+			"github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/b",
+			"github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/c",
+		}},
+		{[]string{"github.com/KyleBanks/depth/..."}, []string{"github.com/KyleBanks/depth", "github.com/KyleBanks/depth/cmd/depth"}},
+		{[]string{"./cmd/depth"}, []string{"github.com/KyleBanks/depth/cmd/depth"}},
+		{[]string{"./...", "."}, []string{"github.com/KyleBanks/depth", "github.com/KyleBanks/depth/cmd/depth"}},
+		{[]string{"container/..."}, []string{"container/heap", "container/list", "container/ring"}},
+	}
+
+	for idx, tt := range tests {
+		names, err := tr.Expand(tt.patterns...)
+		if err != nil {
+			t.Fatalf("[%v] Unexpected error: %v", idx, err)
+		}
+
+		if !reflect.DeepEqual(names, tt.expected) {
+			t.Fatalf("[%v] Unexpected Expand, expected=%v, got=%v", idx, tt.expected, names)
+		}
+	}
+
+	if _, err := tr.Expand("..."); err != ErrInvalidPattern {
+		t.Fatalf("Unexpected error, expected=%v, got=%v", ErrInvalidPattern, err)
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	tests := []struct {
+		gomod    string
+		expected string
+	}{
+		{"module github.com/KyleBanks/depth\n\ngo 1.17\n", "github.com/KyleBanks/depth"},
+		{"// comment\nmodule \"example.com/foo\"\n", "example.com/foo"},
+		{"go 1.17\n", ""},
+	}
+
+	for idx, tt := range tests {
+		if out := modulePath([]byte(tt.gomod)); out != tt.expected {
+			t.Fatalf("[%v] Unexpected modulePath, expected=%v, got=%v", idx, tt.expected, out)
+		}
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"net/...", "net", true},
+		{"net/...", "net/http", true},
+		{"net/...", "netchan", false},
+		{"net/.../httptest", "net/http/httptest", true},
+		{"./...", ".", true},
+		{"./...", "./cmd/depth", true},
+		{"github.com/foo/...", "github.com/bar/foo", false},
+	}
+
+	for idx, tt := range tests {
+		if out := matchPattern(tt.pattern)(tt.name); out != tt.expected {
+			t.Fatalf("[%v] Unexpected match of %v against %v, expected=%v, got=%v", idx, tt.name, tt.pattern, tt.expected, out)
+		}
+	}
+}
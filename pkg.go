@@ -13,15 +13,25 @@ type Pkg struct {
 	Name   string `json:"name"`
 	SrcDir string `json:"-"`
 
-	Internal bool `json:"internal"`
-	Resolved bool `json:"resolved"`
-	Test     bool `json:"-"`
+	Internal bool          `json:"internal"`
+	Resolved bool          `json:"resolved"`
+	Err      *ResolveError `json:"error,omitempty"`
+	Test     bool          `json:"test,omitempty"`
 
 	Tree   *Tree `json:"-"`
 	Parent *Pkg  `json:"-"`
 	Deps   []Pkg `json:"deps"`
 
-	Raw *build.Package `json:"-"`
+	// Files contains the positions of the imports of the Pkg within the source
+	// files of its Parent.
+	Files []Position `json:"files,omitempty"`
+
+	// Symbols contains the exported identifiers of the Pkg referenced by the non-test
+	// source files of its Parent, when the Tree is resolved with ResolveSymbols.
+	Symbols []string `json:"symbols,omitempty"`
+
+	Module *Module        `json:"module,omitempty"`
+	Raw    *build.Package `json:"-"`
 }
 
 // Resolve recursively finds all dependencies for the Pkg and the packages it depends on.
@@ -35,16 +45,17 @@ func (p *Pkg) Resolve(i Importer) {
 		return
 	}
 
-	// Stop resolving imports if we've reached max depth or found a duplicate.
+	// Stop resolving imports if we've reached max depth or found a duplicate. Roots
+	// are always resolved, even when imported by another root.
 	var importMode build.ImportMode
-	if p.Tree.hasSeenImport(name) || p.Tree.isAtMaxDepth(p) {
+	if (p.Tree.hasSeenImport(name) && !p.Tree.isRoot(p)) || p.Tree.isAtMaxDepth(p) {
 		importMode = build.FindOnly
 	}
 
 	pkg, err := i.Import(name, p.SrcDir, importMode)
 	if err != nil {
-		// TODO: Check the error type?
 		p.Resolved = false
+		p.Err = newResolveError(name, pkg, err)
 		return
 	}
 	p.Raw = pkg
@@ -52,6 +63,11 @@ func (p *Pkg) Resolve(i Importer) {
 	// Update the name with the fully qualified import path.
 	p.Name = pkg.ImportPath
 
+	// Module-aware importers can also tell us where the package comes from.
+	if m, ok := i.(ModuleImporter); ok {
+		p.Module = m.Module(pkg.ImportPath)
+	}
+
 	// If this is an internal dependency, we may need to skip it.
 	if pkg.Goroot {
 		p.Internal = true
@@ -62,17 +78,23 @@ func (p *Pkg) Resolve(i Importer) {
 
 	//first we set the regular dependencies, then we add the test dependencies
 	//sharing the same set. This allows us to mark all test-only deps linearly
+	resolveTest := p.Tree.shouldResolveTest(p)
+	pos := positions(pkg.ImportPos)
+	if resolveTest {
+		pos = positions(pkg.ImportPos, pkg.TestImportPos, pkg.XTestImportPos)
+	}
+
 	unique := make(map[string]struct{})
-	p.setDeps(i, pkg.Imports, pkg.Dir, unique, false)
-	if p.Tree.ResolveTest {
-		p.setDeps(i, append(pkg.TestImports, pkg.XTestImports...), pkg.Dir, unique, true)
+	p.setDeps(i, pkg.Imports, pkg.Dir, pos, unique, false)
+	if resolveTest {
+		p.setDeps(i, append(pkg.TestImports, pkg.XTestImports...), pkg.Dir, pos, unique, true)
 	}
 }
 
-// setDeps takes a slice of import paths and the source directory they are relative to,
-// and creates the Deps of the Pkg. Each dependency is also further resolved prior to being added
-// to the Pkg.
-func (p *Pkg) setDeps(i Importer, imports []string, srcDir string, unique map[string]struct{}, isTest bool) {
+// setDeps takes a slice of import paths, the source directory they are relative to and the
+// positions of each import, and creates the Deps of the Pkg. Each dependency is also further
+// resolved prior to being added to the Pkg.
+func (p *Pkg) setDeps(i Importer, imports []string, srcDir string, pos map[string][]Position, unique map[string]struct{}, isTest bool) {
 	for _, imp := range imports {
 		// Mostly for testing files where cyclic imports are allowed.
 		if imp == p.Name {
@@ -85,20 +107,22 @@ func (p *Pkg) setDeps(i Importer, imports []string, srcDir string, unique map[st
 		}
 		unique[imp] = struct{}{}
 
-		p.addDep(i, imp, srcDir, isTest)
+		p.addDep(i, imp, srcDir, pos[imp], isTest)
 	}
 
 	sort.Sort(byInternalAndName(p.Deps))
 }
 
-// addDep creates a Pkg and it's dependencies from an imported package name.
-func (p *Pkg) addDep(i Importer, name string, srcDir string, isTest bool) {
+// addDep creates a Pkg and it's dependencies from an imported package name, and the
+// positions of the import.
+func (p *Pkg) addDep(i Importer, name string, srcDir string, files []Position, isTest bool) {
 	dep := Pkg{
 		Name:   name,
 		SrcDir: srcDir,
 		Tree:   p.Tree,
 		Parent: p,
 		Test:   isTest,
+		Files:  files,
 	}
 	dep.Resolve(i)
 
@@ -132,8 +156,14 @@ func (p *Pkg) depth() int {
 //
 // If an empty string is returned, dependencies should not be resolved.
 func (p *Pkg) cleanName() string {
-	name := p.Name
+	return cleanImportName(p.Name)
+}
 
+// cleanImportName returns a cleaned version of an import path used for resolving
+// dependencies.
+//
+// If an empty string is returned, the import cannot be resolved.
+func cleanImportName(name string) string {
 	// C 'package' cannot be resolved.
 	if name == "C" {
 		return ""
@@ -150,11 +180,18 @@ func (p *Pkg) cleanName() string {
 	return name
 }
 
-// String returns a string representation of the Pkg containing the Pkg name and status.
+// String returns a string representation of the Pkg containing the Pkg name, status
+// and the dependency Module providing it, if known.
 func (p *Pkg) String() string {
 	b := bytes.NewBufferString(p.Name)
 
-	if !p.Resolved {
+	if p.Module != nil && !p.Module.Main {
+		b.WriteString(" (" + p.Module.String() + ")")
+	}
+
+	if p.Err != nil {
+		b.WriteString(" (unresolved: " + string(p.Err.Kind) + ")")
+	} else if !p.Resolved {
 		b.Write([]byte(" (unresolved)"))
 	}
 
@@ -53,11 +53,30 @@ func TestPkg_AddDepImportSeen(t *testing.T) {
 	}
 
 	// Hasn't seen the import
-	p.addDep(m, testName, testSrcDir, false)
+	p.addDep(m, testName, testSrcDir, nil, false)
 
 	// Has seen the import
 	expectedIm = build.FindOnly
-	p.addDep(m, testName, testSrcDir, false)
+	p.addDep(m, testName, testSrcDir, nil, false)
+}
+
+func TestPkg_String(t *testing.T) {
+	tests := []struct {
+		p        Pkg
+		expected string
+	}{
+		{Pkg{Name: "strings", Resolved: true}, "strings"},
+		{Pkg{Name: "notreal"}, "notreal (unresolved)"},
+		{Pkg{Name: "notreal", Err: &ResolveError{Kind: KindMissing}}, "notreal (unresolved: missing)"},
+		{Pkg{Name: "github.com/foo/bar", Resolved: true, Module: &Module{Path: "github.com/foo/bar", Version: "v1.0.0"}}, "github.com/foo/bar (github.com/foo/bar v1.0.0)"},
+		{Pkg{Name: "github.com/foo/bar", Resolved: true, Module: &Module{Path: "github.com/foo/bar", Main: true}}, "github.com/foo/bar"},
+	}
+
+	for idx, tt := range tests {
+		if out := tt.p.String(); out != tt.expected {
+			t.Fatalf("[%v] Unexpected String, expected=%v, got=%v", idx, tt.expected, out)
+		}
+	}
 }
 
 func TestByInternalAndName(t *testing.T) {
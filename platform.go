@@ -0,0 +1,186 @@
+package depth
+
+import (
+	"errors"
+	"fmt"
+	"go/build"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedImporter is returned when the Importer of a Tree cannot be
+// configured for another platform.
+var ErrUnsupportedImporter = errors.New("importer does not support other platforms")
+
+// Platform is a target operating system and architecture.
+type Platform struct {
+	GOOS   string
+	GOARCH string
+}
+
+// ParsePlatform parses a platform in the form "goos/goarch", such as "linux/amd64".
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("invalid platform '%v', expected goos/goarch", s)
+	}
+
+	return Platform{GOOS: parts[0], GOARCH: parts[1]}, nil
+}
+
+// String returns the platform in the form "goos/goarch".
+func (p Platform) String() string {
+	return p.GOOS + "/" + p.GOARCH
+}
+
+// ResolvePlatforms resolves the package patterns provided, as with ResolveAll, once
+// for each of the platforms, and merges the results into the Graph of the Tree. Each
+// Edge of the Graph lists the platforms it applies to.
+//
+// The Roots of the Tree are those of the last platform. The Importer of the Tree must
+// be unset, a *build.Context, a *GoListImporter or a *CachedImporter of either, so that
+// it can be configured for each platform. Cgo is disabled for the platforms other than
+// the host, unless KeepCgo is set.
+func (t *Tree) ResolvePlatforms(platforms []Platform, patterns ...string) error {
+	original := t.Importer
+	defer func() {
+		t.Importer = original
+	}()
+
+	graphs := make([]*Graph, 0, len(platforms))
+	for _, p := range platforms {
+		i, err := t.importerFor(original, p)
+		if err != nil {
+			return err
+		}
+
+		t.Importer = i
+		if err := t.ResolveAll(patterns...); err != nil {
+			return fmt.Errorf("%v: %v", p, err)
+		}
+		graphs = append(graphs, t.Graph)
+	}
+
+	t.Graph = mergeGraphs(platforms, graphs)
+	return nil
+}
+
+// importerFor returns a copy of the Importer provided, configured for the platform.
+func (t *Tree) importerFor(i Importer, p Platform) (Importer, error) {
+	var ctx build.Context
+	switch i := i.(type) {
+	case nil:
+		ctx = build.Default
+		if t.Context != nil {
+			ctx = *t.Context
+		}
+	case *build.Context:
+		ctx = *i
+	case *CachedImporter:
+		inner, err := t.importerFor(i.Importer, p)
+		if err != nil {
+			return nil, err
+		}
+		return &CachedImporter{Importer: inner, Dir: i.Dir}, nil
+	case *GoListImporter:
+		ctx = build.Default
+		if i.Context != nil {
+			ctx = *i.Context
+		}
+		t.configure(&ctx, p)
+		return &GoListImporter{Env: i.Env, Context: &ctx}, nil
+	default:
+		return nil, ErrUnsupportedImporter
+	}
+
+	t.configure(&ctx, p)
+	return &ctx, nil
+}
+
+// configure sets the platform of the build context, disabling cgo for the platforms
+// other than the host unless KeepCgo is set.
+func (t *Tree) configure(ctx *build.Context, p Platform) {
+	ctx.GOOS, ctx.GOARCH = p.GOOS, p.GOARCH
+	if !t.KeepCgo && (p.GOOS != build.Default.GOOS || p.GOARCH != build.Default.GOARCH) {
+		ctx.CgoEnabled = false
+	}
+}
+
+// mergeGraphs combines the Graphs resolved for each of the platforms into a single
+// Graph, recording the platforms of each Edge.
+func mergeGraphs(platforms []Platform, graphs []*Graph) *Graph {
+	m := &Graph{
+		nodes:     make(map[string]*Node),
+		imports:   make(map[string][]Edge),
+		importers: make(map[string][]Edge),
+	}
+
+	for idx, g := range graphs {
+		platform := platforms[idx].String()
+		m.platforms = append(m.platforms, platform)
+
+		for _, r := range g.roots {
+			if !isRoot(m, r) {
+				m.roots = append(m.roots, r)
+			}
+		}
+
+		for _, n := range g.Nodes() {
+			if existing, ok := m.nodes[n.Name]; !ok {
+				copied := *n
+				m.nodes[n.Name] = &copied
+			} else if !existing.Resolved && n.Resolved {
+				existing.Resolved = true
+				existing.Pkg = n.Pkg
+			}
+		}
+
+		for _, e := range g.Edges() {
+			m.addPlatformEdge(e, platform)
+		}
+	}
+
+	for _, edges := range m.imports {
+		m.sortEdges(edges)
+	}
+	m.markTest()
+
+	return m
+}
+
+// addPlatformEdge adds the Edge to the Graph for the platform provided, or adds the
+// platform to the existing Edge.
+func (g *Graph) addPlatformEdge(e Edge, platform string) {
+	for i, existing := range g.imports[e.From] {
+		if existing.To != e.To {
+			continue
+		}
+
+		existing.Platforms = append(existing.Platforms, platform)
+		existing.Test = existing.Test && e.Test
+		existing.Files = mergePositions(existing.Files, e.Files)
+		existing.Symbols = mergeSymbols(existing.Symbols, e.Symbols)
+		g.imports[e.From][i] = existing
+		for j, imp := range g.importers[e.To] {
+			if imp.From == e.From {
+				g.importers[e.To][j] = existing
+			}
+		}
+		return
+	}
+
+	e.Platforms = []string{platform}
+	g.imports[e.From] = append(g.imports[e.From], e)
+	g.importers[e.To] = append(g.importers[e.To], e)
+}
+
+// sortEdges sorts the Edges by their imported Node, in the same order as Nodes.
+func (g *Graph) sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := g.nodes[edges[i].To], g.nodes[edges[j].To]
+		if a.Internal != b.Internal {
+			return a.Internal
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,162 @@
+package depth
+
+import (
+	"go/build"
+	"reflect"
+	"testing"
+)
+
+const platformsPkg = "github.com/KyleBanks/depth/cmd/depth/testdata/platforms"
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected Platform
+		err      bool
+	}{
+		{"linux/amd64", Platform{GOOS: "linux", GOARCH: "amd64"}, false},
+		{"windows/arm64", Platform{GOOS: "windows", GOARCH: "arm64"}, false},
+		{"linux", Platform{}, true},
+		{"linux/", Platform{}, true},
+		{"linux/amd64/v2", Platform{}, true},
+	}
+
+	for idx, tt := range tests {
+		p, err := ParsePlatform(tt.s)
+		if (err != nil) != tt.err {
+			t.Fatalf("[%v] Unexpected error, expected=%v, got=%v", idx, tt.err, err)
+		} else if p != tt.expected {
+			t.Fatalf("[%v] Unexpected Platform, expected=%v, got=%v", idx, tt.expected, p)
+		} else if !tt.err && p.String() != tt.s {
+			t.Fatalf("[%v] Unexpected String, expected=%v, got=%v", idx, tt.s, p.String())
+		}
+	}
+}
+
+func TestTree_ResolveContext(t *testing.T) {
+	ctx := build.Default
+	ctx.GOOS = "windows"
+	ctx.BuildTags = []string{"custom"}
+	ctx.CgoEnabled = false
+
+	tr := Tree{Context: &ctx}
+	if err := tr.Resolve(platformsPkg); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{platformsPkg, "fmt", platformsPkg + "/custom", platformsPkg + "/windows"}
+	if deps := pkgNames(*tr.Root); !reflect.DeepEqual(deps, expected) {
+		t.Fatalf("Unexpected deps, expected=%v, got=%v", expected, deps)
+	}
+}
+
+func TestTree_ResolvePlatforms(t *testing.T) {
+	ctx := build.Default
+	ctx.CgoEnabled = false
+
+	platforms := []Platform{{"linux", "amd64"}, {"darwin", "arm64"}, {"windows", "amd64"}}
+	tr := Tree{Context: &ctx}
+	if err := tr.ResolvePlatforms(platforms, platformsPkg); err != nil {
+		t.Fatal(err)
+	}
+
+	if p := tr.Graph.Platforms(); !reflect.DeepEqual(p, []string{"linux/amd64", "darwin/arm64", "windows/amd64"}) {
+		t.Fatalf("Unexpected Platforms, got=%v", p)
+	}
+
+	expected := map[string][]string{
+		"fmt":                     {"linux/amd64", "darwin/arm64", "windows/amd64"},
+		platformsPkg + "/unix":    {"linux/amd64", "darwin/arm64"},
+		platformsPkg + "/windows": {"windows/amd64"},
+	}
+	edges := tr.Graph.EdgesFrom(platformsPkg)
+	if len(edges) != len(expected) {
+		t.Fatalf("Unexpected Edges length, expected=%v, got=%v", len(expected), len(edges))
+	}
+	for _, e := range edges {
+		if !reflect.DeepEqual(e.Platforms, expected[e.To]) {
+			t.Fatalf("[%v] Unexpected Platforms, expected=%v, got=%v", e.To, expected[e.To], e.Platforms)
+		}
+	}
+
+	if edges[0].To != "fmt" || edges[2].To != platformsPkg+"/windows" {
+		t.Fatalf("Unexpected Edges order, got=%v", edges)
+	} else if n := tr.Graph.ImportersOf(platformsPkg + "/windows"); len(n) != 1 || n[0].Name != platformsPkg {
+		t.Fatalf("Unexpected importers, got=%v", nodeNames(n))
+	} else if tr.Importer != nil {
+		t.Fatalf("Unexpected Importer, expected=nil, got=%v", tr.Importer)
+	}
+}
+
+func TestTree_importerFor(t *testing.T) {
+	p := Platform{GOOS: "windows", GOARCH: "arm64"}
+	ctx := build.Default
+	ctx.BuildTags = []string{"custom"}
+
+	tests := []struct {
+		tree     Tree
+		importer Importer
+		err      error
+	}{
+		{Tree{}, nil, nil},
+		{Tree{Context: &ctx}, nil, nil},
+		{Tree{}, &ctx, nil},
+		{Tree{}, &GoListImporter{Context: &ctx}, nil},
+		{Tree{}, MockImporter{}, ErrUnsupportedImporter},
+	}
+
+	for idx, tt := range tests {
+		i, err := tt.tree.importerFor(tt.importer, p)
+		if err != tt.err {
+			t.Fatalf("[%v] Unexpected error, expected=%v, got=%v", idx, tt.err, err)
+		} else if err != nil {
+			continue
+		}
+
+		var c *build.Context
+		switch i := i.(type) {
+		case *build.Context:
+			c = i
+		case *GoListImporter:
+			c = i.Context
+		}
+		if c == nil || c.GOOS != p.GOOS || c.GOARCH != p.GOARCH {
+			t.Fatalf("[%v] Unexpected Context, expected platform=%v, got=%v", idx, p, c)
+		} else if c == &ctx {
+			t.Fatalf("[%v] Expected Context to be copied", idx)
+		}
+	}
+
+	if ctx.GOOS != build.Default.GOOS {
+		t.Fatalf("Unexpected GOOS, expected=%v, got=%v", build.Default.GOOS, ctx.GOOS)
+	}
+}
+
+func TestTree_importerForCgo(t *testing.T) {
+	ctx := build.Default
+	ctx.CgoEnabled = true
+	host := Platform{GOOS: build.Default.GOOS, GOARCH: build.Default.GOARCH}
+	other := Platform{GOOS: "plan9", GOARCH: "386"}
+
+	tests := []struct {
+		keepCgo  bool
+		platform Platform
+		expected bool
+	}{
+		{false, host, true},
+		{false, other, false},
+		{true, other, true},
+	}
+
+	for idx, tt := range tests {
+		tr := Tree{Context: &ctx, KeepCgo: tt.keepCgo}
+		i, err := tr.importerFor(nil, tt.platform)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c := i.(*build.Context); c.CgoEnabled != tt.expected {
+			t.Fatalf("[%v] Unexpected CgoEnabled, expected=%v, got=%v", idx, tt.expected, c.CgoEnabled)
+		}
+	}
+}
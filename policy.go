@@ -0,0 +1,324 @@
+package depth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Policy is a set of Rules that the dependencies of a Tree must follow, typically
+// read from a JSON configuration file.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule restricts the imports of the packages matching its From pattern.
+//
+// Patterns are import paths that may contain the "..." wildcard, as with Expand.
+// Patterns beginning with "./" or "../" are relative to the Dir of the Tree being
+// checked, and must be within a module.
+type Rule struct {
+	Name string `json:"name"`
+
+	// From selects the packages the Rule applies to. If empty, the Rule applies to
+	// every package.
+	From string `json:"from,omitempty"`
+
+	// Deny contains the patterns of packages that must not be imported, directly or
+	// transitively.
+	Deny []string `json:"deny,omitempty"`
+
+	// NoExternal forbids importing packages outside the stdlib, other than those
+	// matching the Allow patterns.
+	NoExternal bool     `json:"no_external,omitempty"`
+	Allow      []string `json:"allow,omitempty"`
+
+	// MaxDepth limits the number of imports between the packages matching From, or
+	// the roots of the Tree if From is empty, and any of their dependencies.
+	MaxDepth int `json:"max_depth,omitempty"`
+
+	// Layers contains the patterns of ordered architectural layers, from the highest
+	// to the lowest, such as handlers, services, repositories and models. Packages
+	// may only import packages of their own layer or of the layer directly below, and
+	// packages matching none of the Layers are ignored.
+	Layers []string `json:"layers,omitempty"`
+}
+
+// Violation is an import that breaks a Rule.
+type Violation struct {
+	Rule Rule
+	Edge Edge
+
+	// Path is the shortest chain of imports from a root of the Tree through the
+	// offending import.
+	Path []string
+
+	Message string
+}
+
+// ReadPolicy reads a JSON encoded Policy.
+func ReadPolicy(r io.Reader) (*Policy, error) {
+	var p Policy
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// Check returns every import within the resolved Tree that breaks a Rule of the
+// Policy.
+func (p *Policy) Check(t *Tree) ([]Violation, error) {
+	var violations []Violation
+	for _, r := range p.Rules {
+		v, err := r.check(t)
+		if err != nil {
+			return nil, fmt.Errorf("rule '%v': %v", r.Name, err)
+		}
+		violations = append(violations, v...)
+	}
+
+	return violations, nil
+}
+
+// check returns every import within the Tree that breaks the Rule.
+func (r Rule) check(t *Tree) ([]Violation, error) {
+	from, err := t.patternMatcher(r.From)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := t.patternMatchers(r.Deny)
+	if err != nil {
+		return nil, err
+	}
+	allow, err := t.patternMatchers(r.Allow)
+	if err != nil {
+		return nil, err
+	}
+
+	g := t.Graph
+	var violations []Violation
+	for _, e := range g.Edges() {
+		if !from(e.From) {
+			continue
+		}
+
+		if r.NoExternal && !g.Node(e.To).Internal && !matchesAny(allow, e.To) {
+			violations = append(violations, Violation{
+				Rule:    r,
+				Edge:    e,
+				Path:    append(g.RootPath(e.From), e.To),
+				Message: fmt.Sprintf("%v must not import external package %v", e.From, e.To),
+			})
+		}
+	}
+
+	if len(deny) > 0 {
+		violations = append(violations, r.checkDeny(g, from, deny)...)
+	}
+	if r.MaxDepth > 0 {
+		violations = append(violations, r.checkDepth(g, from)...)
+	}
+	if len(r.Layers) > 0 {
+		v, err := r.checkLayers(t, from)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, v...)
+	}
+
+	return violations, nil
+}
+
+// checkDeny returns a Violation for each package matching deny that is imported,
+// directly or transitively, by the packages matched by from. Each denied package is
+// reported once, along with the shortest chain of imports from any of those packages.
+func (r Rule) checkDeny(g *Graph, from func(string) bool, deny []func(string) bool) []Violation {
+	// Denied packages are never the start of a chain, so that they are reported when
+	// From matches them too.
+	paths := make(map[string][]string)
+	var queue []string
+	for _, n := range g.Nodes() {
+		if from(n.Name) && !matchesAny(deny, n.Name) {
+			paths[n.Name] = []string{n.Name}
+			queue = append(queue, n.Name)
+		}
+	}
+
+	var violations []Violation
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.imports[name] {
+			if _, ok := paths[e.To]; ok {
+				continue
+			}
+			path := append(append([]string{}, paths[name]...), e.To)
+			paths[e.To] = path
+			queue = append(queue, e.To)
+
+			if matchesAny(deny, e.To) {
+				violations = append(violations, Violation{
+					Rule:    r,
+					Edge:    e,
+					Path:    append(g.RootPath(path[0]), path[1:]...),
+					Message: fmt.Sprintf("%v must not import %v", path[0], e.To),
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// checkDepth returns a Violation for each import that reaches a package more than
+// MaxDepth imports away from the packages matched by from, or the roots of the Graph
+// if the Rule has no From pattern.
+func (r Rule) checkDepth(g *Graph, from func(string) bool) []Violation {
+	var sources []string
+	if r.From == "" {
+		sources = g.roots
+	} else {
+		for _, n := range g.Nodes() {
+			if from(n.Name) {
+				sources = append(sources, n.Name)
+			}
+		}
+	}
+
+	// Find the shortest chain of imports from any source to each package.
+	paths := make(map[string][]string)
+	queue := append([]string{}, sources...)
+	for _, s := range sources {
+		paths[s] = []string{s}
+	}
+
+	var violations []Violation
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		for _, e := range g.imports[name] {
+			if _, ok := paths[e.To]; ok {
+				continue
+			}
+			paths[e.To] = append(append([]string{}, paths[name]...), e.To)
+			queue = append(queue, e.To)
+
+			if depth := len(paths[e.To]) - 1; depth > r.MaxDepth {
+				violations = append(violations, Violation{
+					Rule:    r,
+					Edge:    e,
+					Path:    append(g.RootPath(paths[e.To][0]), paths[e.To][1:]...),
+					Message: fmt.Sprintf("%v is %d imports deep, the maximum is %d", e.To, depth, r.MaxDepth),
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// checkLayers returns a Violation for each import from a package matched by from
+// that points to a higher layer, or skips the layer directly below its own.
+func (r Rule) checkLayers(t *Tree, from func(string) bool) ([]Violation, error) {
+	layers, err := t.patternMatchers(r.Layers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each package belongs to the first layer it matches, if any.
+	layer := func(name string) int {
+		for i, m := range layers {
+			if m(name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	g := t.Graph
+	var violations []Violation
+	for _, e := range g.Edges() {
+		if !from(e.From) {
+			continue
+		}
+
+		l, to := layer(e.From), layer(e.To)
+		if l < 0 || to < 0 || to == l || to == l+1 {
+			continue
+		}
+
+		var msg string
+		if to < l {
+			msg = fmt.Sprintf("%v must not import %v from the higher layer %v", e.From, e.To, r.Layers[to])
+		} else {
+			msg = fmt.Sprintf("%v must not import %v, skipping layer %v", e.From, e.To, r.Layers[l+1])
+		}
+
+		violations = append(violations, Violation{
+			Rule:    r,
+			Edge:    e,
+			Path:    append(g.RootPath(e.From), e.To),
+			Message: msg,
+		})
+	}
+
+	return violations, nil
+}
+
+// patternMatcher returns a function that reports whether a package name matches the
+// pattern provided. Empty patterns match every package.
+func (t *Tree) patternMatcher(pattern string) (func(string) bool, error) {
+	if pattern == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	if strings.HasPrefix(pattern, "./") || strings.HasPrefix(pattern, "../") || pattern == "." {
+		dir, err := t.srcDir()
+		if err != nil {
+			return nil, err
+		}
+
+		base, wildcard := pattern, ""
+		if strings.HasSuffix(base, "/...") {
+			base, wildcard = strings.TrimSuffix(base, "/..."), "/..."
+		}
+
+		importPath := localImportPath(filepath.Join(dir, base))
+		if importPath == "" {
+			return nil, fmt.Errorf("%v: %v", ErrInvalidPattern, pattern)
+		}
+		pattern = importPath + wildcard
+	}
+
+	return matchPattern(pattern), nil
+}
+
+// patternMatchers returns a matching function for each of the patterns provided.
+func (t *Tree) patternMatchers(patterns []string) ([]func(string) bool, error) {
+	var matchers []func(string) bool
+	for _, p := range patterns {
+		m, err := t.patternMatcher(p)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// matchesAny returns true if the package name matches any of the matchers provided.
+func matchesAny(matchers []func(string) bool, name string) bool {
+	for _, m := range matchers {
+		if m(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package depth
+
+import (
+	"go/build"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	m := mockGraphImporter(map[string][]string{
+		"app":              {"app/api", "app/core"},
+		"app/api":          {"app/core", "app/db"},
+		"app/core":         {"strings", "github.com/x/y", "github.com/x/log"},
+		"app/db":           {"github.com/x/sql"},
+		"strings":          {},
+		"github.com/x/y":   {},
+		"github.com/x/log": {},
+		"github.com/x/sql": {"github.com/x/log"},
+	})
+	tr := Tree{
+		Importer: MockImporter{
+			ImportFn: func(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+				pkg, err := m.Import(name, srcDir, im)
+				if pkg != nil && !strings.Contains(name, ".") && !strings.HasPrefix(name, "app") {
+					pkg.Goroot = true
+				}
+				return pkg, err
+			},
+		},
+	}
+	if err := tr.Resolve("app"); err != nil {
+		t.Fatal(err)
+	}
+
+	p := Policy{Rules: []Rule{
+		{Name: "no-db-in-api", From: "app/api", Deny: []string{"app/db/..."}},
+		{Name: "core", From: "app/core/...", NoExternal: true, Allow: []string{"github.com/x/log"}},
+		{Name: "depth", MaxDepth: 2},
+		{Name: "no-sql", From: "app", Deny: []string{"github.com/x/sql"}},
+	}}
+
+	violations, err := p.Check(&tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		rule string
+		path []string
+	}{
+		{"no-db-in-api", []string{"app", "app/api", "app/db"}},
+		{"core", []string{"app", "app/core", "github.com/x/y"}},
+		{"depth", []string{"app", "app/api", "app/db", "github.com/x/sql"}},
+		{"no-sql", []string{"app", "app/api", "app/db", "github.com/x/sql"}},
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("Unexpected number of Violations, expected=%v, got=%+v", len(expected), violations)
+	}
+	for i, e := range expected {
+		if violations[i].Rule.Name != e.rule || !reflect.DeepEqual(violations[i].Path, e.path) {
+			t.Fatalf("[%v] Unexpected Violation, expected=%v %v, got=%v %v", i, e.rule, e.path, violations[i].Rule.Name, violations[i].Path)
+		}
+	}
+	if violations[0].Message != "app/api must not import app/db" {
+		t.Fatalf("Unexpected Message, got=%v", violations[0].Message)
+	}
+	if v := violations[3]; v.Message != "app must not import github.com/x/sql" || v.Edge.From != "app/db" {
+		t.Fatalf("Unexpected transitive Violation, got=%v from %v", v.Message, v.Edge.From)
+	}
+}
+
+func TestPolicy_CheckLayers(t *testing.T) {
+	tr := Tree{
+		Importer: mockGraphImporter(map[string][]string{
+			"app/handlers":      {"app/services", "app/repos", "app/util"},
+			"app/services":      {"app/repos", "app/services/auth"},
+			"app/services/auth": {},
+			"app/repos":         {"app/models"},
+			"app/models":        {"app/services/auth"},
+			"app/util":          {"app/handlers/errs"},
+			"app/handlers/errs": {},
+		}),
+	}
+	if err := tr.Resolve("app/handlers"); err != nil {
+		t.Fatal(err)
+	}
+
+	p := Policy{Rules: []Rule{
+		{Name: "layers", Layers: []string{"app/handlers/...", "app/services/...", "app/repos/...", "app/models/..."}},
+	}}
+
+	violations, err := p.Check(&tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		path    []string
+		message string
+	}{
+		{[]string{"app/handlers", "app/repos"}, "app/handlers must not import app/repos, skipping layer app/services/..."},
+		{[]string{"app/handlers", "app/repos", "app/models", "app/services/auth"}, "app/models must not import app/services/auth from the higher layer app/services/..."},
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("Unexpected number of Violations, expected=%v, got=%+v", len(expected), violations)
+	}
+	for i, e := range expected {
+		if !reflect.DeepEqual(violations[i].Path, e.path) || violations[i].Message != e.message {
+			t.Fatalf("[%v] Unexpected Violation, expected=%v %v, got=%v %v", i, e.path, e.message, violations[i].Path, violations[i].Message)
+		}
+	}
+}
+
+func TestReadPolicy(t *testing.T) {
+	p, err := ReadPolicy(strings.NewReader(`{"rules": [{"name": "a", "from": "./...", "deny": ["github.com/x/..."], "max_depth": 3}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Rule{Name: "a", From: "./...", Deny: []string{"github.com/x/..."}, MaxDepth: 3}
+	if len(p.Rules) != 1 || !reflect.DeepEqual(p.Rules[0], expected) {
+		t.Fatalf("Unexpected Policy, expected=%+v, got=%+v", expected, p.Rules)
+	}
+
+	if _, err := ReadPolicy(strings.NewReader(`{"rules": [{"nme": "a"}]}`)); err == nil {
+		t.Fatal("Expected error for unknown field")
+	}
+}
+
+func TestTree_patternMatcher(t *testing.T) {
+	var tr Tree
+
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"", "anything", true},
+		{"./...", "github.com/KyleBanks/depth/cmd/depth", true},
+		{"./cmd/...", "github.com/KyleBanks/depth", false},
+		{"./cmd/depth", "github.com/KyleBanks/depth/cmd/depth", true},
+		{"github.com/KyleBanks/...", "github.com/KyleBanks/depth", true},
+	}
+
+	for idx, tt := range tests {
+		m, err := tr.patternMatcher(tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := m(tt.name); out != tt.expected {
+			t.Fatalf("[%v] Unexpected match of %v against %v, expected=%v, got=%v", idx, tt.name, tt.pattern, tt.expected, out)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package depth
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"sort"
+)
+
+// Position is the location of an import within the source files of a package.
+type Position struct {
+	// File is the name of the source file, relative to the directory of the package.
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
+}
+
+// String returns the Position in the form "file:line", or only the file if the line
+// is unknown.
+func (p Position) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+	return fmt.Sprintf("%v:%v", p.File, p.Line)
+}
+
+// positions converts the import positions reported by go/build into Positions of each
+// import path, combining the positions of each map provided.
+func positions(maps ...map[string][]token.Position) map[string][]Position {
+	pos := make(map[string][]Position)
+	for _, m := range maps {
+		for imp, tokens := range m {
+			for _, t := range tokens {
+				pos[imp] = append(pos[imp], Position{File: filepath.Base(t.Filename), Line: t.Line})
+			}
+		}
+	}
+
+	for imp := range pos {
+		pos[imp] = sortPositions(pos[imp])
+	}
+	return pos
+}
+
+// mergePositions returns the Positions of both slices, without duplicates.
+func mergePositions(a, b []Position) []Position {
+	merged := append([]Position{}, a...)
+	for _, p := range b {
+		found := false
+		for _, existing := range a {
+			if p == existing {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, p)
+		}
+	}
+	return sortPositions(merged)
+}
+
+// sortPositions sorts the Positions by file and line.
+func sortPositions(pos []Position) []Position {
+	sort.Slice(pos, func(i, j int) bool {
+		if pos[i].File != pos[j].File {
+			return pos[i].File < pos[j].File
+		}
+		return pos[i].Line < pos[j].Line
+	})
+	return pos
+}
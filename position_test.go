@@ -0,0 +1,77 @@
+package depth
+
+import (
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestPosition_String(t *testing.T) {
+	tests := []struct {
+		p        Position
+		expected string
+	}{
+		{Position{File: "depth.go", Line: 12}, "depth.go:12"},
+		{Position{File: "depth.go"}, "depth.go"},
+	}
+
+	for idx, tt := range tests {
+		if out := tt.p.String(); out != tt.expected {
+			t.Fatalf("[%v] Unexpected String, expected=%v, got=%v", idx, tt.expected, out)
+		}
+	}
+}
+
+func Test_positions(t *testing.T) {
+	imports := map[string][]token.Position{
+		"strings": {{Filename: "/src/foo/foo.go", Line: 5}, {Filename: "/src/foo/bar.go", Line: 4}},
+		"fmt":     {{Filename: "/src/foo/foo.go", Line: 4}},
+	}
+	tests := map[string][]token.Position{
+		"strings": {{Filename: "/src/foo/bar_test.go", Line: 3}},
+		"testing": {{Filename: "/src/foo/bar_test.go", Line: 4}},
+	}
+
+	expected := map[string][]Position{
+		"fmt":     {{File: "foo.go", Line: 4}},
+		"strings": {{File: "bar.go", Line: 4}, {File: "bar_test.go", Line: 3}, {File: "foo.go", Line: 5}},
+		"testing": {{File: "bar_test.go", Line: 4}},
+	}
+	if pos := positions(imports, tests, nil); !reflect.DeepEqual(pos, expected) {
+		t.Fatalf("Unexpected positions, expected=%v, got=%v", expected, pos)
+	}
+}
+
+func Test_mergePositions(t *testing.T) {
+	a := []Position{{File: "a.go", Line: 3}, {File: "c.go", Line: 1}}
+	b := []Position{{File: "c.go", Line: 1}, {File: "b.go", Line: 7}}
+
+	expected := []Position{{File: "a.go", Line: 3}, {File: "b.go", Line: 7}, {File: "c.go", Line: 1}}
+	if merged := mergePositions(a, b); !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("Unexpected positions, expected=%v, got=%v", expected, merged)
+	} else if len(a) != 2 {
+		t.Fatalf("Unexpected modification of positions, got=%v", a)
+	}
+}
+
+func TestTree_ResolveFiles(t *testing.T) {
+	const name = "github.com/KyleBanks/depth/cmd/depth/testdata/cycles/a"
+
+	importers := []Importer{nil, &GoListImporter{}}
+	for idx, i := range importers {
+		tr := Tree{ResolveTest: true, Importer: i}
+		if err := tr.Resolve(name); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []Position{{File: "a_test.go", Line: 6}}
+		if files := tr.Root.Deps[1].Files; !reflect.DeepEqual(files, expected) {
+			t.Fatalf("[%v] Unexpected Files, expected=%v, got=%v", idx, expected, files)
+		}
+
+		edges := tr.Graph.EdgesFrom(name)
+		if len(edges) != 2 || !reflect.DeepEqual(edges[1].Files, expected) {
+			t.Fatalf("[%v] Unexpected Edge Files, expected=%v, got=%v", idx, expected, edges)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package depth
+
+import (
+	"go/build"
+	"sync"
+)
+
+// prefetcher is an Importer that imports packages concurrently, ahead of the
+// sequential resolution of the Tree.
+//
+// Each fetched package also schedules the packages it imports, so the graph is
+// discovered by a pool of workers while the Tree consumes the results in its usual
+// order. This keeps the resulting Tree identical to a sequential resolution.
+type prefetcher struct {
+	Importer
+
+	tree *Tree
+	sem  chan struct{}
+	wg   sync.WaitGroup
+
+	mu      sync.Mutex
+	results map[string]*importResult
+}
+
+// importResult is the outcome of a prefetched import.
+type importResult struct {
+	srcDir string
+	pkg    *build.Package
+	err    error
+	done   chan struct{}
+}
+
+// newPrefetcher returns a prefetcher for the Tree that imports at most workers
+// packages at a time using the Importer provided.
+func newPrefetcher(t *Tree, i Importer, workers int) *prefetcher {
+	return &prefetcher{
+		Importer: i,
+		tree:     t,
+		sem:      make(chan struct{}, workers),
+		results:  make(map[string]*importResult),
+	}
+}
+
+// fetch schedules the import of name relative to srcDir, at the depth provided,
+// unless the name has already been scheduled.
+func (f *prefetcher) fetch(name, srcDir string, depth int) {
+	name = cleanImportName(name)
+	if name == "" {
+		return
+	}
+
+	f.mu.Lock()
+	if _, ok := f.results[name]; ok {
+		f.mu.Unlock()
+		return
+	}
+	r := &importResult{srcDir: srcDir, done: make(chan struct{})}
+	f.results[name] = r
+	f.mu.Unlock()
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		f.sem <- struct{}{}
+		r.pkg, r.err = f.Importer.Import(name, srcDir, 0)
+		<-f.sem
+		close(r.done)
+
+		if r.err != nil || !f.shouldFetchImports(r.pkg, depth) {
+			return
+		}
+
+		for _, imp := range r.pkg.Imports {
+			f.fetch(imp, r.pkg.Dir, depth+1)
+		}
+		if f.shouldFetchTestImports(r.pkg, depth) {
+			for _, imp := range append(r.pkg.TestImports, r.pkg.XTestImports...) {
+				f.fetch(imp, r.pkg.Dir, depth+1)
+			}
+		}
+	}()
+}
+
+// shouldFetchImports determines if the imports of a package fetched at the depth
+// provided are going to be resolved by the Tree, mirroring the rules of
+// shouldResolveInternal and isAtMaxDepth.
+func (f *prefetcher) shouldFetchImports(pkg *build.Package, depth int) bool {
+	if pkg.Goroot && depth > 0 && !f.tree.ResolveInternal {
+		return false
+	}
+
+	return f.tree.MaxDepth == 0 || depth+1 < f.tree.MaxDepth
+}
+
+// shouldFetchTestImports determines if the test imports of a package fetched at the
+// depth provided are going to be resolved by the Tree, mirroring shouldResolveTest.
+func (f *prefetcher) shouldFetchTestImports(pkg *build.Package, depth int) bool {
+	if f.tree.ResolveTest {
+		return true
+	}
+	if !f.tree.ResolveMainTest {
+		return false
+	}
+	if depth == 0 {
+		return true
+	}
+
+	m, ok := f.Importer.(ModuleImporter)
+	if !ok {
+		return false
+	}
+	mod := m.Module(pkg.ImportPath)
+	return mod != nil && mod.Main
+}
+
+// Import returns the result of a prefetched import, waiting for it to complete if
+// necessary. Packages that weren't prefetched, or were fetched relative to another
+// source directory, are imported directly, as are packages only being located whose
+// prefetched import failed, since locating them can still succeed.
+func (f *prefetcher) Import(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+	f.mu.Lock()
+	r, ok := f.results[name]
+	f.mu.Unlock()
+
+	if ok {
+		<-r.done
+		if r.err == nil && (r.srcDir == srcDir || r.pkg.Goroot) {
+			if im&build.FindOnly != 0 {
+				return findOnly(r.pkg), nil
+			}
+			return r.pkg, nil
+		} else if r.srcDir == srcDir && im&build.FindOnly == 0 {
+			return r.pkg, r.err
+		}
+	}
+
+	return f.Importer.Import(name, srcDir, im)
+}
+
+// findOnly returns a copy of the package containing only the details located by
+// the build.FindOnly import mode.
+func findOnly(pkg *build.Package) *build.Package {
+	return &build.Package{
+		Dir:           pkg.Dir,
+		ImportPath:    pkg.ImportPath,
+		Root:          pkg.Root,
+		SrcRoot:       pkg.SrcRoot,
+		PkgRoot:       pkg.PkgRoot,
+		PkgTargetRoot: pkg.PkgTargetRoot,
+		BinDir:        pkg.BinDir,
+		Goroot:        pkg.Goroot,
+		PkgObj:        pkg.PkgObj,
+		ConflictDir:   pkg.ConflictDir,
+	}
+}
+
+// Module implements the ModuleImporter interface, if supported by the underlying
+// Importer.
+func (f *prefetcher) Module(importPath string) *Module {
+	if m, ok := f.Importer.(ModuleImporter); ok {
+		return m.Module(importPath)
+	}
+	return nil
+}
+
+// wait blocks until all scheduled imports have completed.
+func (f *prefetcher) wait() {
+	f.wg.Wait()
+}
@@ -0,0 +1,149 @@
+package depth
+
+import (
+	"go/build"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestTree_ResolveWorkers(t *testing.T) {
+	for _, name := range []string{"strings", "github.com/KyleBanks/depth/cmd/depth"} {
+		seq := Tree{ResolveInternal: true, ResolveTest: true}
+		if err := seq.Resolve(name); err != nil {
+			t.Fatal(err)
+		}
+
+		par := Tree{ResolveInternal: true, ResolveTest: true, Workers: 8}
+		if err := par.Resolve(name); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, got := pkgNames(*seq.Root), pkgNames(*par.Root); !reflect.DeepEqual(expected, got) {
+			t.Fatalf("[%v] Unexpected concurrent resolution, expected=%v, got=%v", name, expected, got)
+		}
+	}
+}
+
+func TestTree_ResolveWorkersNoGoFiles(t *testing.T) {
+	// As with go/build, a package without Go files can be located but not imported.
+	importer := MockImporter{
+		ImportFn: func(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+			deps := map[string][]string{"a": {"b", "c"}, "b": {"c"}}
+			pkg := &build.Package{ImportPath: name, Dir: name}
+			if im&build.FindOnly != 0 {
+				return pkg, nil
+			}
+			if name == "c" {
+				return pkg, &build.NoGoError{Dir: name}
+			}
+			pkg.Name, pkg.Imports = name, deps[name]
+			return pkg, nil
+		},
+	}
+
+	var expected []string
+	for _, workers := range []int{1, 8} {
+		tr := Tree{Importer: importer, Workers: workers}
+		if err := tr.Resolve("a"); err != nil {
+			t.Fatal(err)
+		}
+
+		got := pkgStrings(*tr.Root)
+		if expected == nil {
+			expected = got
+		} else if !reflect.DeepEqual(expected, got) {
+			t.Fatalf("Unexpected resolution with %v workers, expected=%v, got=%v", workers, expected, got)
+		}
+	}
+}
+
+func TestPrefetcher_Import(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+
+	m := MockImporter{
+		ImportFn: func(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+			mu.Lock()
+			calls[name]++
+			mu.Unlock()
+
+			switch name {
+			case "a":
+				return &build.Package{ImportPath: "a", Dir: "src/a", Imports: []string{"b", "C"}}, nil
+			case "b":
+				return &build.Package{ImportPath: "b", Dir: "src/b", Imports: []string{"a"}}, nil
+			}
+			return &build.Package{ImportPath: name}, nil
+		},
+	}
+
+	f := newPrefetcher(&Tree{}, m, 2)
+	f.fetch("a", "src", 0)
+	f.wait()
+
+	if calls["a"] != 1 || calls["b"] != 1 || calls["C"] != 0 {
+		t.Fatalf("Unexpected prefetched imports, got=%v", calls)
+	}
+
+	// Prefetched results are reused when the source directory matches.
+	if pkg, err := f.Import("b", "src/a", 0); err != nil || pkg.ImportPath != "b" {
+		t.Fatalf("Unexpected Import, got=%v, %v", pkg, err)
+	} else if calls["b"] != 1 {
+		t.Fatalf("Expected prefetched import to be reused, got=%v calls", calls["b"])
+	}
+
+	// FindOnly imports only locate the prefetched package.
+	if pkg, err := f.Import("b", "src/a", build.FindOnly); err != nil || pkg.ImportPath != "b" || len(pkg.Imports) != 0 {
+		t.Fatalf("Unexpected FindOnly Import, got=%v, %v", pkg, err)
+	} else if calls["b"] != 1 {
+		t.Fatalf("Expected prefetched import to be reused, got=%v calls", calls["b"])
+	}
+
+	// Other source directories are imported directly.
+	f.Import("b", "src/other", 0)
+	if calls["b"] != 2 {
+		t.Fatalf("Expected direct import, got=%v calls", calls["b"])
+	}
+}
+
+func TestPrefetcher_shouldFetchImports(t *testing.T) {
+	tests := []struct {
+		tree     Tree
+		goroot   bool
+		depth    int
+		expected bool
+	}{
+		{Tree{}, false, 0, true},
+		{Tree{}, true, 0, true},
+		{Tree{}, true, 1, false},
+		{Tree{ResolveInternal: true}, true, 1, true},
+		{Tree{MaxDepth: 2}, false, 0, true},
+		{Tree{MaxDepth: 2}, false, 1, false},
+	}
+
+	for idx, tt := range tests {
+		f := newPrefetcher(&tt.tree, nil, 1)
+		if out := f.shouldFetchImports(&build.Package{Goroot: tt.goroot}, tt.depth); out != tt.expected {
+			t.Fatalf("[%v] Unexpected shouldFetchImports, expected=%v, got=%v", idx, tt.expected, out)
+		}
+	}
+}
+
+// pkgNames returns the names of the Pkg and all of its dependencies, in tree order.
+// pkgStrings returns the String of the Pkg and each of its dependencies, recursively.
+func pkgStrings(p Pkg) []string {
+	s := []string{p.String()}
+	for _, d := range p.Deps {
+		s = append(s, pkgStrings(d)...)
+	}
+	return s
+}
+
+func pkgNames(p Pkg) []string {
+	names := []string{p.Name}
+	for _, d := range p.Deps {
+		names = append(names, pkgNames(d)...)
+	}
+	return names
+}
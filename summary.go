@@ -0,0 +1,76 @@
+package depth
+
+// Summary counts the unique dependencies within a Tree.
+type Summary struct {
+	Internal int `json:"internal"`
+	External int `json:"external"`
+	Testing  int `json:"testing"`
+}
+
+// Total returns the total number of dependencies.
+func (s Summary) Total() int {
+	return s.Internal + s.External
+}
+
+// Summary counts the unique dependencies of the Roots of the Tree. The Roots
+// themselves are not counted.
+//
+// Each dependency is counted once, and is counted as a testing dependency if it is
+// first encountered as a test import.
+func (t *Tree) Summary() Summary {
+	var sum Summary
+
+	seen := make(map[string]struct{})
+	for _, r := range t.roots() {
+		seen[r.Name] = struct{}{}
+	}
+	for _, r := range t.roots() {
+		for _, p := range r.Deps {
+			sum.collect(p, seen)
+		}
+	}
+	return sum
+}
+
+// Summary counts the Nodes of the Graph, other than its roots. Nodes are counted as
+// testing dependencies if they are only reachable through test imports.
+func (g *Graph) Summary() Summary {
+	var sum Summary
+	for _, n := range g.nodes {
+		if isRoot(g, n.Name) {
+			continue
+		}
+
+		if n.Internal {
+			sum.Internal++
+		} else {
+			sum.External++
+		}
+		if n.Test {
+			sum.Testing++
+		}
+	}
+	return sum
+}
+
+// collect recursively counts the Pkg and its dependencies, unless they have already
+// been seen.
+func (s *Summary) collect(p Pkg, seen map[string]struct{}) {
+	if _, ok := seen[p.Name]; ok {
+		return
+	}
+	seen[p.Name] = struct{}{}
+
+	if p.Internal {
+		s.Internal++
+	} else {
+		s.External++
+	}
+	if p.Test {
+		s.Testing++
+	}
+
+	for _, d := range p.Deps {
+		s.collect(d, seen)
+	}
+}
@@ -0,0 +1,52 @@
+package depth
+
+import (
+	"testing"
+)
+
+func TestTree_Summary(t *testing.T) {
+	tr := Tree{
+		Root: &Pkg{
+			Name: "root",
+			Deps: []Pkg{
+				{Name: "strings", Internal: true},
+				{Name: "github.com/foo/bar", Deps: []Pkg{
+					{Name: "strings", Internal: true},
+					{Name: "github.com/foo/baz"},
+				}},
+				{Name: "testing", Internal: true, Test: true},
+			},
+		},
+	}
+
+	expected := Summary{Internal: 2, External: 2, Testing: 1}
+	if sum := tr.Summary(); sum != expected {
+		t.Fatalf("Unexpected Summary, expected=%+v, got=%+v", expected, sum)
+	} else if sum.Total() != 4 {
+		t.Fatalf("Unexpected Total, expected=%v, got=%v", 4, sum.Total())
+	}
+
+	var empty Tree
+	if sum := empty.Summary(); sum != (Summary{}) {
+		t.Fatalf("Unexpected Summary for unresolved Tree, got=%+v", sum)
+	}
+}
+
+func TestGraph_Summary(t *testing.T) {
+	root := Pkg{
+		Name: "root",
+		Deps: []Pkg{
+			{Name: "strings", Internal: true},
+			{Name: "github.com/foo/bar", Deps: []Pkg{
+				{Name: "strings", Internal: true},
+				{Name: "root"},
+			}},
+			{Name: "testing", Internal: true, Test: true},
+		},
+	}
+
+	expected := Summary{Internal: 2, External: 1, Testing: 1}
+	if sum := newGraph(&root).Summary(); sum != expected {
+		t.Fatalf("Unexpected Summary, expected=%+v, got=%+v", expected, sum)
+	}
+}
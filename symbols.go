@@ -0,0 +1,222 @@
+package depth
+
+import (
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+)
+
+// errImportCycle is returned when type-checking packages that import each other.
+var errImportCycle = errors.New("import cycle")
+
+// resolveSymbols type-checks each resolved Pkg within the Tree, and sets the Symbols
+// of its dependencies to the exported identifiers it references from each of them.
+func (t *Tree) resolveSymbols() {
+	ctx := build.Default
+	if t.Context != nil {
+		ctx = *t.Context
+	}
+
+	ti := &typesImporter{
+		importer: t.importer(),
+		fset:     token.NewFileSet(),
+		sizes:    types.SizesFor("gc", ctx.GOARCH),
+		pkgs:     make(map[string]*types.Package),
+		paths:    make(map[string]string),
+	}
+	for _, r := range t.Roots {
+		ti.resolve(r)
+	}
+}
+
+// resolve recursively sets the Symbols of the dependencies of the Pkg. Only the copy of
+// each package whose dependencies were resolved is type-checked, and only its non-test
+// imports have Symbols.
+func (ti *typesImporter) resolve(p *Pkg) {
+	var used map[string][]string
+	for i := range p.Deps {
+		d := &p.Deps[i]
+		if !d.Test && p.Raw != nil {
+			if used == nil {
+				used = ti.symbols(p.Raw)
+			}
+			d.Symbols = used[d.Name]
+		}
+
+		ti.resolve(d)
+	}
+}
+
+// typesImporter imports packages for type-checking by parsing their source files, as
+// located by the Importer of a Tree. Only the declarations of imported packages are
+// checked, not their function bodies.
+type typesImporter struct {
+	importer Importer
+	fset     *token.FileSet
+	sizes    types.Sizes
+
+	// pkgs contains each imported package by its import path, or nil while it is
+	// being checked, and paths contains the import path of each import relative to
+	// its source directory.
+	pkgs  map[string]*types.Package
+	paths map[string]string
+}
+
+// Import imports the package with the path provided, relative to the current directory.
+func (ti *typesImporter) Import(path string) (*types.Package, error) {
+	return ti.ImportFrom(path, "", 0)
+}
+
+// ImportFrom imports the package with the path provided, relative to the source
+// directory of the importing package.
+func (ti *typesImporter) ImportFrom(path, dir string, _ types.ImportMode) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+
+	key := dir + "\x00" + path
+	if importPath, ok := ti.paths[key]; ok {
+		if p := ti.pkgs[importPath]; p != nil {
+			return p, nil
+		}
+		return nil, errImportCycle
+	}
+
+	pkg, err := ti.importer.Import(path, dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	ti.paths[key] = pkg.ImportPath
+
+	// The same package may be imported from another directory, or by another path.
+	if p, ok := ti.pkgs[pkg.ImportPath]; ok {
+		if p == nil {
+			return nil, errImportCycle
+		}
+		return p, nil
+	}
+
+	ti.pkgs[pkg.ImportPath] = nil
+	p := ti.check(pkg, nil)
+	ti.pkgs[pkg.ImportPath] = p
+	return p, nil
+}
+
+// check type-checks the non-test source files of the package, ignoring errors so that
+// as much of the package as possible is checked. Function bodies are only checked if
+// the Info is provided.
+func (ti *typesImporter) check(pkg *build.Package, info *types.Info) *types.Package {
+	var files []*ast.File
+	for _, name := range append(append([]string{}, pkg.GoFiles...), pkg.CgoFiles...) {
+		// Files with syntax errors are partially parsed, and still checked.
+		if f, _ := parser.ParseFile(ti.fset, filepath.Join(pkg.Dir, name), nil, 0); f != nil {
+			files = append(files, f)
+		}
+	}
+
+	conf := types.Config{
+		Importer:         ti,
+		Sizes:            ti.sizes,
+		FakeImportC:      true,
+		IgnoreFuncBodies: info == nil,
+		Error:            func(error) {},
+	}
+	p, _ := conf.Check(pkg.ImportPath, ti.fset, files, info)
+	return p
+}
+
+// symbols type-checks the package, and returns the exported identifiers it references
+// from each imported package, by import path. Methods are named after their receiver
+// type, such as "Builder.WriteString", and struct fields aren't included.
+func (ti *typesImporter) symbols(pkg *build.Package) map[string][]string {
+	info := &types.Info{Uses: make(map[*ast.Ident]types.Object)}
+	p := ti.check(pkg, info)
+
+	used := make(map[string][]string)
+	for _, obj := range info.Uses {
+		if obj.Pkg() == nil || obj.Pkg() == p || !obj.Exported() {
+			continue
+		}
+
+		if name := symbolName(obj); name != "" {
+			path := obj.Pkg().Path()
+			used[path] = append(used[path], name)
+		}
+	}
+
+	for path, names := range used {
+		used[path] = uniqueStrings(names)
+	}
+	return used
+}
+
+// symbolName returns the name of a package-level object or method, or an empty string
+// for any other object.
+func symbolName(obj types.Object) string {
+	if f, ok := obj.(*types.Func); ok {
+		recv := f.Type().(*types.Signature).Recv()
+		if recv == nil {
+			return f.Name()
+		}
+
+		typ := recv.Type()
+		if ptr, ok := typ.(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		if named, ok := typ.(*types.Named); ok {
+			return named.Obj().Name() + "." + f.Name()
+		}
+		return f.Name()
+	}
+
+	if obj.Parent() != obj.Pkg().Scope() {
+		return ""
+	}
+	return obj.Name()
+}
+
+// uniqueStrings returns the strings sorted, without duplicates.
+func uniqueStrings(s []string) []string {
+	sort.Strings(s)
+	unique := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// mergeSymbols returns the symbols of both slices, sorted and without duplicates.
+func mergeSymbols(a, b []string) []string {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	return uniqueStrings(append(append([]string{}, a...), b...))
+}
+
+// NarrowImports returns the non-test imports of external packages that reference
+// between one and max exported identifiers of the imported package, ordered by the
+// importing Node. These are candidates for vendoring or removal. Imports referencing
+// no identifiers, such as those only imported for their side effects, aren't included.
+//
+// The Symbols of each Edge are only known when the Tree was resolved with
+// ResolveSymbols.
+func (g *Graph) NarrowImports(max int) []Edge {
+	var edges []Edge
+	for _, e := range g.Edges() {
+		if e.Test || len(e.Symbols) == 0 || len(e.Symbols) > max {
+			continue
+		}
+		if n := g.nodes[e.To]; n == nil || n.Internal {
+			continue
+		}
+		edges = append(edges, e)
+	}
+	return edges
+}
@@ -0,0 +1,108 @@
+package depth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTree_ResolveSymbols(t *testing.T) {
+	const prefix = "github.com/KyleBanks/depth/cmd/depth/testdata/symbols/"
+
+	tr := Tree{ResolveSymbols: true}
+	if err := tr.Resolve(prefix + "app"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		to      string
+		symbols []string
+	}{
+		{"strings", []string{"Builder", "Builder.String", "Builder.WriteString", "Join"}},
+		{prefix + "narrow", []string{"Name"}},
+		{prefix + "side", nil},
+		{prefix + "wide", []string{"Counter", "Counter.Add", "Counter.String", "Name", "Step"}},
+	}
+
+	for _, tt := range tests {
+		var found bool
+		for _, e := range tr.Graph.EdgesFrom(tr.Root.Name) {
+			if e.To != tt.to {
+				continue
+			}
+
+			found = true
+			if !reflect.DeepEqual(e.Symbols, tt.symbols) {
+				t.Fatalf("[%v] Unexpected Symbols, expected=%v, got=%v", tt.to, tt.symbols, e.Symbols)
+			}
+		}
+		if !found {
+			t.Fatalf("[%v] Expected an Edge", tt.to)
+		}
+	}
+
+	// Dependencies are type-checked along with the roots.
+	for _, e := range tr.Graph.EdgesFrom(prefix + "wide") {
+		if expected := []string{"Itoa"}; !reflect.DeepEqual(e.Symbols, expected) {
+			t.Fatalf("[%v] Unexpected Symbols, expected=%v, got=%v", e.To, expected, e.Symbols)
+		}
+	}
+}
+
+func TestTree_ResolveSymbolsDisabled(t *testing.T) {
+	var tr Tree
+	if err := tr.Resolve("github.com/KyleBanks/depth/cmd/depth/testdata/symbols/app"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range tr.Graph.Edges() {
+		if e.Symbols != nil {
+			t.Fatalf("[%v] Unexpected Symbols, expected=%v, got=%v", e.To, nil, e.Symbols)
+		}
+	}
+}
+
+func TestGraph_NarrowImports(t *testing.T) {
+	const prefix = "github.com/KyleBanks/depth/cmd/depth/testdata/symbols/"
+
+	tr := Tree{ResolveSymbols: true}
+	if err := tr.Resolve(prefix + "app"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		max      int
+		expected []string
+	}{
+		{0, nil},
+		{2, []string{prefix + "narrow"}},
+		{5, []string{prefix + "narrow", prefix + "wide"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, e := range tr.Graph.NarrowImports(tt.max) {
+			got = append(got, e.To)
+		}
+
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("[%v] Unexpected imports, expected=%v, got=%v", tt.max, tt.expected, got)
+		}
+	}
+}
+
+func Test_mergeSymbols(t *testing.T) {
+	tests := []struct {
+		a, b     []string
+		expected []string
+	}{
+		{nil, nil, nil},
+		{[]string{"B", "A"}, nil, []string{"A", "B"}},
+		{[]string{"A", "C"}, []string{"B", "C"}, []string{"A", "B", "C"}},
+	}
+
+	for _, tt := range tests {
+		if got := mergeSymbols(tt.a, tt.b); !reflect.DeepEqual(got, tt.expected) {
+			t.Fatalf("[%v %v] Unexpected symbols, expected=%v, got=%v", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
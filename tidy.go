@@ -0,0 +1,212 @@
+package depth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoMod contains the module path and requirements of a go.mod file.
+type GoMod struct {
+	Module  string
+	Require []Requirement
+}
+
+// Requirement is a module required by a go.mod file.
+type Requirement struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+
+	// Indirect is true when the requirement is marked with an "// indirect" comment.
+	Indirect bool `json:"indirect,omitempty"`
+}
+
+// String returns the path and version of the Requirement.
+func (r Requirement) String() string {
+	return r.Path + " " + r.Version
+}
+
+// ReadGoMod reads the go.mod file at the path provided.
+func ReadGoMod(path string) (*GoMod, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseGoMod(f)
+}
+
+// ParseGoMod parses the module path and requirements of a go.mod file, ignoring its
+// other directives, including replace and exclude, so the Requirements are listed as
+// written rather than as replaced. Comments are stripped from the first "//" of each
+// line, even within a quoted path, which is enough for the paths and versions of
+// modules but not for arbitrary go.mod files.
+func ParseGoMod(r io.Reader) (*GoMod, error) {
+	var mod GoMod
+	var block string
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text, comment := s.Text(), ""
+		if i := strings.Index(text, "//"); i >= 0 {
+			text, comment = text[:i], strings.TrimSpace(text[i+2:])
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Directives can be grouped in a block, such as "require ( ... )".
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			fields = append([]string{block}, fields...)
+		} else if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+
+		switch fields[0] {
+		case "module":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("go.mod:%d: invalid module directive", line)
+			}
+			mod.Module = unquoteGoMod(fields[1])
+		case "require":
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("go.mod:%d: invalid require directive", line)
+			}
+			mod.Require = append(mod.Require, Requirement{
+				Path:     unquoteGoMod(fields[1]),
+				Version:  unquoteGoMod(fields[2]),
+				Indirect: comment == "indirect" || strings.HasPrefix(comment, "indirect;"),
+			})
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return &mod, nil
+}
+
+// unquoteGoMod returns a go.mod token without its quotes, if it is quoted.
+func unquoteGoMod(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
+
+// TidyReport lists the requirements of a go.mod file that don't match the modules
+// reached from the packages of the main module.
+type TidyReport struct {
+	// Unused contains the requirements providing none of the packages reached.
+	Unused []Requirement `json:"unused"`
+
+	// TestOnly contains the requirements whose packages are only reached through
+	// the test imports of the main module.
+	TestOnly []Requirement `json:"testOnly"`
+
+	// Direct contains the requirements marked as indirect, but whose packages are
+	// imported by the packages of the main module.
+	Direct []Requirement `json:"direct"`
+}
+
+// Empty returns true if the TidyReport doesn't list any requirements.
+func (r TidyReport) Empty() bool {
+	return len(r.Unused) == 0 && len(r.TestOnly) == 0 && len(r.Direct) == 0
+}
+
+// Tidy compares the requirements of the go.mod file provided with the modules of the
+// packages in the Graph. The Tree must be resolved from the packages of the module,
+// such as with ResolveAll("./..."), by a ModuleImporter, and with ResolveTest or
+// ResolveMainTest set so that test imports are reached.
+//
+// Only the test imports of the packages of the main module are followed, as those of
+// other modules aren't needed to build or test the main module.
+func (g *Graph) Tidy(mod *GoMod) TidyReport {
+	const (
+		unreached = iota
+		reachedTest
+		reachedBuild
+	)
+
+	isMain := func(n *Node) bool {
+		return isRoot(g, n.Name) || (n.Pkg.Module != nil && n.Pkg.Module.Main)
+	}
+
+	// Each package is reached by a build if any path to it avoids test imports.
+	// Packages are revisited when reached by a build after a test.
+	reached := make(map[string]int)
+	direct := make(map[string]bool)
+	queue := make([]string, 0, len(g.roots))
+	for _, r := range g.roots {
+		reached[r] = reachedBuild
+		queue = append(queue, r)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		from := g.nodes[name]
+		for _, e := range g.imports[name] {
+			if e.Test && !isMain(from) {
+				continue
+			}
+
+			to := g.nodes[e.To]
+			if isMain(from) && to.Pkg.Module != nil {
+				direct[to.Pkg.Module.Path] = true
+			}
+
+			state := reachedBuild
+			if e.Test || reached[name] == reachedTest {
+				state = reachedTest
+			}
+			if reached[e.To] < state {
+				reached[e.To] = state
+				queue = append(queue, e.To)
+			}
+		}
+	}
+
+	modules := make(map[string]int)
+	for name, state := range reached {
+		n := g.nodes[name]
+		if n.Pkg.Module == nil || n.Pkg.Module.Main {
+			continue
+		}
+		if path := n.Pkg.Module.Path; modules[path] < state {
+			modules[path] = state
+		}
+	}
+
+	var r TidyReport
+	for _, req := range mod.Require {
+		switch modules[req.Path] {
+		case unreached:
+			r.Unused = append(r.Unused, req)
+		case reachedTest:
+			r.TestOnly = append(r.TestOnly, req)
+		}
+
+		if req.Indirect && direct[req.Path] {
+			r.Direct = append(r.Direct, req)
+		}
+	}
+
+	for _, reqs := range [][]Requirement{r.Unused, r.TestOnly, r.Direct} {
+		sort.Slice(reqs, func(i, j int) bool {
+			return reqs[i].Path < reqs[j].Path
+		})
+	}
+	return r
+}
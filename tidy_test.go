@@ -0,0 +1,124 @@
+package depth
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGoMod(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected *GoMod
+		err      string
+	}{
+		{
+			name: "block",
+			src: `module example.com/app // the app
+
+go 1.17
+
+require (
+	example.com/a v1.0.0
+	"example.com/b" v1.1.0 // indirect
+	example.com/c v0.1.0 // indirect; required by example.com/a
+)
+
+require example.com/d v2.0.0+incompatible // indirect
+
+replace example.com/a => ../a
+
+exclude (
+	example.com/e v1.0.0
+)
+`,
+			expected: &GoMod{
+				Module: "example.com/app",
+				Require: []Requirement{
+					{Path: "example.com/a", Version: "v1.0.0"},
+					{Path: "example.com/b", Version: "v1.1.0", Indirect: true},
+					{Path: "example.com/c", Version: "v0.1.0", Indirect: true},
+					{Path: "example.com/d", Version: "v2.0.0+incompatible", Indirect: true},
+				},
+			},
+		},
+		{
+			name:     "empty",
+			src:      "module example.com/app\n",
+			expected: &GoMod{Module: "example.com/app"},
+		},
+		{
+			name: "invalid require",
+			src:  "module example.com/app\n\nrequire example.com/a\n",
+			err:  "go.mod:3: invalid require directive",
+		},
+		{
+			name: "invalid module",
+			src:  "module\n",
+			err:  "go.mod:1: invalid module directive",
+		},
+	}
+
+	for _, tt := range tests {
+		mod, err := ParseGoMod(strings.NewReader(tt.src))
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("[%v] Unexpected error, expected=%v, got=%v", tt.name, tt.err, err)
+			}
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(mod, tt.expected) {
+			t.Fatalf("[%v] Unexpected GoMod, expected=%+v, got=%+v", tt.name, tt.expected, mod)
+		}
+	}
+}
+
+func TestGraph_Tidy(t *testing.T) {
+	const dir = "cmd/depth/testdata/tidy/app"
+
+	tr := Tree{
+		Dir:         dir,
+		ResolveTest: true,
+		Importer:    &GoListImporter{Env: []string{"GOFLAGS=-mod=vendor"}},
+	}
+	if err := tr.ResolveAll("./..."); err != nil {
+		t.Fatal(err)
+	}
+
+	mod, err := ReadGoMod(dir + "/go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TidyReport{
+		Unused:   []Requirement{{Path: "example.com/unused", Version: "v0.3.0"}},
+		TestOnly: []Requirement{{Path: "example.com/testonly", Version: "v1.2.0"}},
+		Direct:   []Requirement{{Path: "example.com/direct", Version: "v1.0.0", Indirect: true}},
+	}
+	if r := tr.Graph.Tidy(mod); !reflect.DeepEqual(r, expected) {
+		t.Fatalf("Unexpected TidyReport, expected=%+v, got=%+v", expected, r)
+	} else if r.Empty() {
+		t.Fatal("Expected the TidyReport not to be Empty")
+	}
+
+	// Without test imports, the test-only requirement isn't reached at all.
+	tr.ResolveTest = false
+	if err := tr.ResolveAll("./..."); err != nil {
+		t.Fatal(err)
+	}
+
+	r := tr.Graph.Tidy(mod)
+	if unused := []Requirement{{Path: "example.com/testonly", Version: "v1.2.0"}, {Path: "example.com/unused", Version: "v0.3.0"}}; !reflect.DeepEqual(r.Unused, unused) {
+		t.Fatalf("Unexpected Unused, expected=%+v, got=%+v", unused, r.Unused)
+	}
+}
+
+func TestTidyReport_Empty(t *testing.T) {
+	if !(TidyReport{}).Empty() {
+		t.Fatal("Expected an empty TidyReport to be Empty")
+	}
+}
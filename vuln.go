@@ -0,0 +1,430 @@
+package depth
+
+import (
+	"encoding/json"
+	"go/build"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stdlibModule is the module name used by the Go vulnerability database for the
+// packages of the standard library.
+const stdlibModule = "stdlib"
+
+// Vuln is a vulnerability in the OSV format, as published by the Go vulnerability
+// database.
+type Vuln struct {
+	ID       string         `json:"id"`
+	Aliases  []string       `json:"aliases,omitempty"`
+	Summary  string         `json:"summary,omitempty"`
+	Details  string         `json:"details,omitempty"`
+	Affected []VulnAffected `json:"affected"`
+}
+
+// VulnAffected describes the versions of a module affected by a Vuln.
+type VulnAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+
+	Ranges   []VulnRange `json:"ranges,omitempty"`
+	Versions []string    `json:"versions,omitempty"`
+
+	// EcosystemSpecific lists the vulnerable packages of the module, and the
+	// vulnerable symbols of each. If no packages are listed, every package of the
+	// module is vulnerable.
+	EcosystemSpecific struct {
+		Imports []struct {
+			Path    string   `json:"path"`
+			Symbols []string `json:"symbols,omitempty"`
+		} `json:"imports,omitempty"`
+	} `json:"ecosystem_specific"`
+}
+
+// VulnRange is a range of affected versions, delimited by its Events.
+type VulnRange struct {
+	Type   string      `json:"type"`
+	Events []VulnEvent `json:"events"`
+}
+
+// VulnEvent introduces or fixes a Vuln at a version. Versions are in the semantic
+// versioning format, without the "v" prefix used by Go modules, and an introduced
+// version of "0" covers every version.
+type VulnEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// VulnFinding is a module version within a resolved Tree that is affected by a Vuln.
+type VulnFinding struct {
+	Vuln *Vuln
+
+	// Module and Version identify the affected module, using "stdlib" for the
+	// standard library.
+	Module  string
+	Version string
+
+	// Fixed is the earliest version that fixes the Vuln, if any.
+	Fixed string
+
+	// Package is the vulnerable package imported within the Tree, along with its
+	// vulnerable Symbols, if known. Path is the shortest chain of imports from a root
+	// of the Tree to the Package.
+	//
+	// If the Tree uses the module but none of its vulnerable packages, Package and
+	// Path are empty.
+	Package string
+	Symbols []string
+	Path    []string
+}
+
+// Imported returns true if a vulnerable package is imported within the Tree.
+func (f VulnFinding) Imported() bool {
+	return f.Package != ""
+}
+
+// VulnDB is a set of vulnerabilities in the OSV format.
+type VulnDB struct {
+	Vulns []*Vuln
+}
+
+// ReadVulnDB reads every OSV entry within a directory, such as an offline copy of the
+// Go vulnerability database. JSON files that aren't OSV entries, such as indexes, are
+// ignored.
+func ReadVulnDB(dir string) (*VulnDB, error) {
+	var db VulnDB
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		var v Vuln
+		if err := json.Unmarshal(b, &v); err != nil || v.ID == "" || len(v.Affected) == 0 {
+			return nil
+		}
+		db.Vulns = append(db.Vulns, &v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(db.Vulns, func(i, j int) bool {
+		return db.Vulns[i].ID < db.Vulns[j].ID
+	})
+	return &db, nil
+}
+
+// Check returns the findings of every Vuln affecting the module versions used by the
+// resolved Tree, ordered by Vuln and package.
+//
+// Modules are only known when the Tree is resolved by a ModuleImporter, such as the
+// GoListImporter. The version of the standard library is read from GOROOT, and the
+// standard library packages only imported by other standard library packages are
+// only checked when the Tree is resolved with ResolveInternal.
+func (db *VulnDB) Check(t *Tree) []VulnFinding {
+	g := t.Graph
+	goVersion := goSemver(t.goroot())
+
+	// Group the packages of the Graph by the module version providing them.
+	type moduleVersion struct{ path, version string }
+	modules := make(map[moduleVersion][]*Node)
+	var order []moduleVersion
+	for _, n := range g.Nodes() {
+		mv := moduleVersion{stdlibModule, goVersion}
+		if m := n.Pkg.Module; m != nil {
+			if m.Replace != nil {
+				m = m.Replace
+			}
+			mv = moduleVersion{m.Path, m.Version}
+		} else if !n.Internal {
+			continue
+		}
+		if mv.version == "" {
+			continue
+		}
+
+		if _, ok := modules[mv]; !ok {
+			order = append(order, mv)
+		}
+		modules[mv] = append(modules[mv], n)
+	}
+
+	var findings []VulnFinding
+	for _, v := range db.Vulns {
+		for _, a := range v.Affected {
+			if a.Package.Ecosystem != "Go" {
+				continue
+			}
+
+			for _, mv := range order {
+				if mv.path != a.Package.Name || !a.affects(mv.version) {
+					continue
+				}
+
+				f := VulnFinding{
+					Vuln:    v,
+					Module:  mv.path,
+					Version: mv.version,
+					Fixed:   a.fixed(mv.version),
+				}
+				imported := a.imported(modules[mv])
+				for _, i := range imported {
+					f.Package, f.Symbols = i.path, i.symbols
+					f.Path = g.RootPath(i.path)
+					findings = append(findings, f)
+				}
+				if len(imported) == 0 {
+					findings = append(findings, f)
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// vulnImport is a vulnerable package and its vulnerable symbols.
+type vulnImport struct {
+	path    string
+	symbols []string
+}
+
+// imported returns the vulnerable packages among the packages of an affected module.
+func (a VulnAffected) imported(pkgs []*Node) []vulnImport {
+	var imported []vulnImport
+	if len(a.EcosystemSpecific.Imports) == 0 {
+		for _, n := range pkgs {
+			imported = append(imported, vulnImport{path: n.Name})
+		}
+		return imported
+	}
+
+	names := make(map[string]bool)
+	for _, n := range pkgs {
+		names[n.Name] = true
+	}
+	for _, i := range a.EcosystemSpecific.Imports {
+		if names[i.Path] {
+			imported = append(imported, vulnImport{path: i.Path, symbols: i.Symbols})
+		}
+	}
+
+	sort.Slice(imported, func(i, j int) bool {
+		return imported[i].path < imported[j].path
+	})
+	return imported
+}
+
+// affects returns true if the module version, such as "v1.2.3", is affected.
+func (a VulnAffected) affects(version string) bool {
+	v := strings.TrimPrefix(version, "v")
+	for _, listed := range a.Versions {
+		if listed == v {
+			return true
+		}
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type == "SEMVER" && r.affects(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixed returns the earliest version, with a "v" prefix, that fixes the affected
+// module version, if any.
+func (a VulnAffected) fixed(version string) string {
+	v := strings.TrimPrefix(version, "v")
+	var fixed string
+	for _, r := range a.Ranges {
+		for _, e := range r.Events {
+			if e.Fixed != "" && compareSemver(e.Fixed, v) > 0 && (fixed == "" || compareSemver(e.Fixed, fixed) < 0) {
+				fixed = e.Fixed
+			}
+		}
+	}
+
+	if fixed == "" {
+		return ""
+	}
+	return "v" + fixed
+}
+
+// affects returns true if the version, without a "v" prefix, is within the range.
+// Events are applied in order of their versions, so a range can introduce and fix a
+// Vuln several times.
+func (r VulnRange) affects(v string) bool {
+	events := append([]VulnEvent{}, r.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return compareSemver(events[i].version(), events[j].version()) < 0
+	})
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || compareSemver(v, e.Introduced) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if compareSemver(v, e.Fixed) >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if compareSemver(v, e.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// version returns the version of the event, with "0" sorting before any other version.
+func (e VulnEvent) version() string {
+	switch {
+	case e.Introduced == "0":
+		return ""
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	}
+	return e.LastAffected
+}
+
+// goroot returns the GOROOT the Tree is resolved with.
+func (t *Tree) goroot() string {
+	if t.Context != nil {
+		return t.Context.GOROOT
+	}
+	return build.Default.GOROOT
+}
+
+// goSemver returns the version of the Go installation in the directory provided, such
+// as "go1.21rc2", in the semantic versioning format, such as "v1.21.0-rc.2".
+func goSemver(goroot string) string {
+	b, err := os.ReadFile(filepath.Join(goroot, "VERSION"))
+	if err != nil {
+		return ""
+	}
+
+	// Development builds, such as "devel go1.22-abc123", don't have a version.
+	v := strings.TrimSpace(strings.SplitN(string(b), "\n", 2)[0])
+	if !strings.HasPrefix(v, "go") {
+		return ""
+	}
+	v = strings.TrimPrefix(v, "go")
+
+	var pre string
+	for _, tag := range []string{"rc", "beta"} {
+		if i := strings.Index(v, tag); i >= 0 {
+			v, pre = v[:i], "-"+tag+"."+v[i+len(tag):]
+			break
+		}
+	}
+
+	if strings.Count(v, ".") == 1 {
+		v += ".0"
+	}
+	if strings.Count(v, ".") != 2 {
+		return ""
+	}
+	return "v" + v + pre
+}
+
+// compareSemver compares two semantic versions, without the "v" prefix, returning -1,
+// 0 or 1 when the first is lower than, equal to or greater than the second. An empty
+// version is lower than any other, and build metadata is ignored.
+func compareSemver(a, b string) int {
+	if a == b {
+		return 0
+	} else if a == "" {
+		return -1
+	} else if b == "" {
+		return 1
+	}
+
+	a, b = strings.SplitN(a, "+", 2)[0], strings.SplitN(b, "+", 2)[0]
+	aCore, aPre := splitPrerelease(a)
+	bCore, bPre := splitPrerelease(b)
+
+	if c := compareIdentifiers(strings.Split(aCore, "."), strings.Split(bCore, "."), true); c != 0 {
+		return c
+	}
+
+	// A version without a prerelease is greater than any of its prereleases.
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareIdentifiers(strings.Split(aPre, "."), strings.Split(bPre, "."), false)
+}
+
+// splitPrerelease splits a version into its core and prerelease.
+func splitPrerelease(v string) (string, string) {
+	if i := strings.Index(v, "-"); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
+
+// compareIdentifiers compares dot-separated version identifiers. Numeric identifiers
+// are compared numerically and are lower than alphanumeric ones. If pad is true,
+// missing identifiers are treated as zero, otherwise fewer identifiers are lower.
+func compareIdentifiers(a, b []string, pad bool) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if i >= len(a) || i >= len(b) {
+			if pad {
+				if i >= len(a) {
+					a = append(a, "0")
+				} else {
+					b = append(b, "0")
+				}
+			} else if i >= len(a) {
+				return -1
+			} else {
+				return 1
+			}
+		}
+
+		an, aErr := strconv.Atoi(a[i])
+		bn, bErr := strconv.Atoi(b[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
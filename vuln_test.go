@@ -0,0 +1,258 @@
+package depth
+
+import (
+	"encoding/json"
+	"go/build"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// mockModuleImporter is a ModuleImporter reporting the modules of packages from a map
+// of import paths.
+type mockModuleImporter struct {
+	MockImporter
+	modules map[string]*Module
+}
+
+func (m mockModuleImporter) Module(importPath string) *Module {
+	return m.modules[importPath]
+}
+
+// writeVulnDB writes each Vuln, decoded from JSON, to a directory in the layout of the
+// Go vulnerability database.
+func writeVulnDB(t *testing.T, vulns ...string) string {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "ID"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range vulns {
+		var id struct{ ID string }
+		if err := json.Unmarshal([]byte(v), &id); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "ID", id.ID+".json"), []byte(v), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Indexes aren't OSV entries and must be ignored.
+	if err := os.WriteFile(filepath.Join(dir, "modules.json"), []byte(`[{"path":"example.com/lib"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestReadVulnDB(t *testing.T) {
+	dir := writeVulnDB(t,
+		`{"id":"GO-2-B","affected":[{"package":{"ecosystem":"Go","name":"b"}}]}`,
+		`{"id":"GO-1-A","aliases":["CVE-1"],"affected":[{"package":{"ecosystem":"Go","name":"a"}}]}`,
+		`{"id":"GO-3-EMPTY","affected":[]}`,
+	)
+
+	db, err := ReadVulnDB(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, v := range db.Vulns {
+		ids = append(ids, v.ID)
+	}
+	if expect := []string{"GO-1-A", "GO-2-B"}; !reflect.DeepEqual(ids, expect) {
+		t.Fatalf("Unexpected Vulns, expected=%v, got=%v", expect, ids)
+	} else if !reflect.DeepEqual(db.Vulns[0].Aliases, []string{"CVE-1"}) {
+		t.Fatalf("Unexpected Aliases, expected=%v, got=%v", []string{"CVE-1"}, db.Vulns[0].Aliases)
+	}
+
+	if _, err := ReadVulnDB(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("Expected error for missing directory")
+	}
+}
+
+func TestVulnDB_Check(t *testing.T) {
+	tr := Tree{
+		Importer: mockModuleImporter{
+			MockImporter: mockGraphImporter(map[string][]string{
+				"app":                 {"example.com/lib/a", "example.com/other", "example.com/fork"},
+				"example.com/lib/a":   {"example.com/lib/b"},
+				"example.com/lib/b":   {},
+				"example.com/other":   {},
+				"example.com/fork":    {},
+				"example.com/unknown": {},
+			}),
+			modules: map[string]*Module{
+				"app":               {Path: "app", Main: true},
+				"example.com/lib/a": {Path: "example.com/lib", Version: "v1.2.0"},
+				"example.com/lib/b": {Path: "example.com/lib", Version: "v1.2.0"},
+				"example.com/other": {Path: "example.com/other", Version: "v0.1.0"},
+				"example.com/fork":  {Path: "example.com/fork", Version: "v1.0.0", Replace: &Module{Path: "example.com/forked", Version: "v2.0.0+incompatible"}},
+			},
+		},
+	}
+	if err := tr.Resolve("app"); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := writeVulnDB(t,
+		// Affects the imported package b.
+		`{"id":"GO-1","affected":[{"package":{"ecosystem":"Go","name":"example.com/lib"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.2.1"},{"introduced":"1.3.0"},{"fixed":"1.3.2"}]}],"ecosystem_specific":{"imports":[{"path":"example.com/lib/b","symbols":["Parse"]},{"path":"example.com/lib/c"}]}}]}`,
+		// Affects the module, but only a package that isn't imported.
+		`{"id":"GO-2","affected":[{"package":{"ecosystem":"Go","name":"example.com/lib"},"ranges":[{"type":"SEMVER","events":[{"introduced":"1.0.0"}]}],"ecosystem_specific":{"imports":[{"path":"example.com/lib/c"}]}}]}`,
+		// Fixed in the version used.
+		`{"id":"GO-3","affected":[{"package":{"ecosystem":"Go","name":"example.com/other"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"0.1.0"}]}]}]}`,
+		// Affects every package of the replacement module.
+		`{"id":"GO-4","affected":[{"package":{"ecosystem":"Go","name":"example.com/forked"},"versions":["2.0.0+incompatible"]}]}`,
+		// Another ecosystem.
+		`{"id":"PYSEC-1","affected":[{"package":{"ecosystem":"PyPI","name":"example.com/other"},"ranges":[{"type":"ECOSYSTEM","events":[{"introduced":"0"}]}]}]}`,
+	)
+	db, err := ReadVulnDB(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type finding struct {
+		ID, Module, Version, Fixed, Package string
+		Symbols, Path                       []string
+	}
+	expect := []finding{
+		{"GO-1", "example.com/lib", "v1.2.0", "v1.2.1", "example.com/lib/b", []string{"Parse"}, []string{"app", "example.com/lib/a", "example.com/lib/b"}},
+		{"GO-2", "example.com/lib", "v1.2.0", "", "", nil, nil},
+		{"GO-4", "example.com/forked", "v2.0.0+incompatible", "", "example.com/fork", nil, []string{"app", "example.com/fork"}},
+	}
+
+	var got []finding
+	for _, f := range db.Check(&tr) {
+		got = append(got, finding{f.Vuln.ID, f.Module, f.Version, f.Fixed, f.Package, f.Symbols, f.Path})
+		if f.Imported() != (f.Package != "") {
+			t.Fatalf("[%v] Unexpected Imported, got=%v", f.Vuln.ID, f.Imported())
+		}
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Fatalf("Unexpected findings, expected=%+v, got=%+v", expect, got)
+	}
+}
+
+func TestVulnDB_CheckStdlib(t *testing.T) {
+	goroot := t.TempDir()
+	if err := os.WriteFile(filepath.Join(goroot, "VERSION"), []byte("go1.21.3\ntime 2023-10-09T17:04:35Z\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := build.Default
+	ctx.GOROOT = goroot
+
+	tr := Tree{
+		Context: &ctx,
+		Importer: MockImporter{
+			ImportFn: func(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+				pkg := &build.Package{ImportPath: name, Name: name, Goroot: name != "app"}
+				if name == "app" {
+					pkg.Imports = []string{"net/http"}
+				}
+				return pkg, nil
+			},
+		},
+	}
+	if err := tr.Resolve("app"); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := ReadVulnDB(writeVulnDB(t,
+		`{"id":"GO-1","affected":[{"package":{"ecosystem":"Go","name":"stdlib"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.20.10"},{"introduced":"1.21.0-0"},{"fixed":"1.21.4"}]}],"ecosystem_specific":{"imports":[{"path":"net/http"}]}}]}`,
+		`{"id":"GO-2","affected":[{"package":{"ecosystem":"Go","name":"stdlib"},"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.21.3"}]}]}]}`,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := db.Check(&tr)
+	if len(findings) != 1 {
+		t.Fatalf("Unexpected findings, expected=1, got=%+v", findings)
+	} else if f := findings[0]; f.Vuln.ID != "GO-1" || f.Version != "v1.21.3" || f.Fixed != "v1.21.4" || f.Package != "net/http" {
+		t.Fatalf("Unexpected finding, got=%+v", f)
+	}
+}
+
+func TestVulnRange_affects(t *testing.T) {
+	r := VulnRange{Type: "SEMVER", Events: []VulnEvent{
+		{Fixed: "1.2.0"},
+		{Introduced: "0"},
+		{Introduced: "2.0.0"},
+		{LastAffected: "2.1.0"},
+	}}
+
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{"0.0.1", true},
+		{"1.2.0-rc.1", true},
+		{"1.2.0", false},
+		{"1.9.9", false},
+		{"2.0.0", true},
+		{"2.1.0", true},
+		{"2.1.1", false},
+	}
+
+	for idx, tt := range tests {
+		if got := r.affects(tt.version); got != tt.expected {
+			t.Fatalf("[%v] Unexpected affects for %v, expected=%v, got=%v", idx, tt.version, tt.expected, got)
+		}
+	}
+}
+
+func Test_compareSemver(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0", "10.0.0", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"0.0.0-20200101000000-abcdef", "0.1.0", -1},
+		{"2.0.0+incompatible", "2.0.0", 0},
+		{"", "0.0.1", -1},
+		{"0.0.1", "", 1},
+	}
+
+	for idx, tt := range tests {
+		if got := compareSemver(tt.a, tt.b); got != tt.expected {
+			t.Fatalf("[%v] Unexpected compareSemver(%v, %v), expected=%v, got=%v", idx, tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
+
+func Test_goSemver(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"go1.21.3\ntime 2023-10-09T17:04:35Z\n", "v1.21.3"},
+		{"go1.21", "v1.21.0"},
+		{"go1.21rc2", "v1.21.0-rc.2"},
+		{"go1.20beta1", "v1.20.0-beta.1"},
+		{"devel go1.22-abc123", ""},
+	}
+
+	for idx, tt := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "VERSION"), []byte(tt.version), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := goSemver(dir); got != tt.expected {
+			t.Fatalf("[%v] Unexpected goSemver, expected=%v, got=%v", idx, tt.expected, got)
+		}
+	}
+
+	if got := goSemver(t.TempDir()); got != "" {
+		t.Fatalf("Unexpected goSemver without VERSION, expected none, got=%v", got)
+	}
+}
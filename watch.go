@@ -0,0 +1,316 @@
+package depth
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the interval a Watcher polls for changes at when none is set.
+const DefaultWatchInterval = time.Second
+
+// Watcher resolves the packages matching its Patterns, and re-resolves them when the
+// source files of any resolved package change.
+//
+// Imported packages are kept in memory between resolutions, and only the packages
+// whose directory changed are imported again, so re-resolving after an edit is cheap
+// even for large trees. Packages in GOROOT are never watched, and imports that can't
+// be located are retried whenever the directory of their importer changes.
+type Watcher struct {
+	// Tree contains the configuration used to resolve each Tree, which is copied for
+	// every resolution.
+	Tree *Tree
+
+	// Patterns are the packages or package patterns to resolve, as with ResolveAll.
+	Patterns []string
+
+	// Interval sets how often the directories are polled for changes. If zero,
+	// DefaultWatchInterval is used.
+	Interval time.Duration
+
+	importer *watchImporter
+	names    []string
+}
+
+// WatchEvent describes a resolution of a Watcher.
+type WatchEvent struct {
+	// Prev is the previously resolved Tree, or nil for the first resolution.
+	Prev *Tree
+	// Tree is the newly resolved Tree, or nil if the Patterns couldn't be expanded.
+	Tree *Tree
+	// Changed contains the names of the packages that changed since Prev was resolved.
+	Changed []string
+	// Err is the error returned while resolving the Tree, if any.
+	Err error
+}
+
+// Resolve resolves a new Tree from the Patterns, importing only the packages that
+// changed since the previous resolution.
+func (w *Watcher) Resolve() (*Tree, error) {
+	if w.importer == nil {
+		w.importer = newWatchImporter(w.Tree.importer())
+	}
+
+	names, err := w.Tree.Expand(w.Patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, ErrNoPackages
+	}
+	w.names = names
+
+	t := *w.Tree
+	t.Importer = w.importer
+	err = t.resolve(names)
+
+	// Packages that are no longer imported don't need to be watched.
+	resolved := make(map[string]bool)
+	for _, n := range t.Graph.Nodes() {
+		resolved[n.Name] = true
+	}
+	w.importer.retain(resolved)
+
+	return &t, err
+}
+
+// Changed returns the names of the resolved packages whose source files changed since
+// they were imported, and of any packages that started or stopped matching the Patterns.
+// The changed packages are imported again by the next call to Resolve.
+func (w *Watcher) Changed() []string {
+	if w.importer == nil {
+		return nil
+	}
+
+	changed := w.importer.invalidate()
+	if names, err := w.Tree.Expand(w.Patterns...); err == nil && strings.Join(names, " ") != strings.Join(w.names, " ") {
+		changed = append(changed, symmetricDifference(w.names, names)...)
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// Watch resolves the Tree and calls fn with the result, then polls for changes and
+// calls fn again with each new Tree until stop is closed.
+//
+// When re-resolving fails, fn is called with the error and the previous Tree is kept
+// for comparison with the next resolution. An error is only returned if the first
+// resolution fails.
+func (w *Watcher) Watch(stop <-chan struct{}, fn func(WatchEvent)) error {
+	t, err := w.Resolve()
+	if err != nil {
+		return err
+	}
+	fn(WatchEvent{Tree: t})
+
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		changed := w.Changed()
+		if len(changed) == 0 {
+			continue
+		}
+
+		next, err := w.Resolve()
+		fn(WatchEvent{Prev: t, Tree: next, Changed: changed, Err: err})
+		if err == nil {
+			t = next
+		}
+	}
+}
+
+// watchImporter is an Importer that keeps every successfully imported package in
+// memory, and records the state of each package directory so that changed packages
+// can be invalidated.
+type watchImporter struct {
+	Importer
+
+	mu       sync.Mutex
+	packages map[watchKey]*build.Package
+	dirs     map[string]watchedDir
+}
+
+// watchKey identifies an import by its arguments.
+type watchKey struct {
+	name, srcDir string
+	mode         build.ImportMode
+}
+
+// watchedDir is the state of a package directory when it was imported.
+type watchedDir struct {
+	name  string
+	stamp string
+}
+
+// newWatchImporter returns a watchImporter of packages imported by the Importer provided.
+func newWatchImporter(i Importer) *watchImporter {
+	return &watchImporter{
+		Importer: i,
+		packages: make(map[watchKey]*build.Package),
+		dirs:     make(map[string]watchedDir),
+	}
+}
+
+// Import implements the Importer interface, returning the package previously imported
+// with the same arguments unless its directory changed since.
+func (w *watchImporter) Import(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+	k := watchKey{name: name, srcDir: srcDir, mode: im}
+	w.mu.Lock()
+	pkg, ok := w.packages[k]
+	w.mu.Unlock()
+	if ok {
+		return pkg, nil
+	}
+
+	pkg, err := w.Importer.Import(name, srcDir, im)
+	if pkg == nil || pkg.Dir == "" {
+		// Imports that can't be located have no directory of their own, so the
+		// directory of their importer is watched instead.
+		if err != nil && srcDir != "" {
+			w.watchMissing(name, srcDir)
+		}
+		return pkg, err
+	}
+
+	// Failed imports aren't kept, but their directory is still watched so that the
+	// Tree is resolved again once they are fixed.
+	var stamp string
+	if !pkg.Goroot {
+		stamp = dirStamp(pkg.Dir)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err == nil {
+		w.packages[k] = pkg
+	}
+	if _, ok := w.dirs[pkg.Dir]; !pkg.Goroot && (!ok || im&build.FindOnly == 0) {
+		w.dirs[pkg.Dir] = watchedDir{name: pkg.ImportPath, stamp: stamp}
+	}
+	return pkg, err
+}
+
+// watchMissing watches the directory of the importer of a package that couldn't be
+// located, unless it is already watched.
+func (w *watchImporter) watchMissing(name, srcDir string) {
+	stamp := dirStamp(srcDir)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.dirs[srcDir]; !ok {
+		w.dirs[srcDir] = watchedDir{name: name, stamp: stamp}
+	}
+}
+
+// Module implements the ModuleImporter interface, if supported by the underlying
+// Importer.
+func (w *watchImporter) Module(importPath string) *Module {
+	if m, ok := w.Importer.(ModuleImporter); ok {
+		return m.Module(importPath)
+	}
+	return nil
+}
+
+// invalidate removes the packages whose directories changed since they were imported,
+// and returns their names.
+func (w *watchImporter) invalidate() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changed := make(map[string]bool)
+	var names []string
+	for dir, d := range w.dirs {
+		if dirStamp(dir) != d.stamp {
+			changed[dir] = true
+			names = append(names, d.name)
+			delete(w.dirs, dir)
+		}
+	}
+
+	for k, pkg := range w.packages {
+		if changed[pkg.Dir] {
+			delete(w.packages, k)
+		}
+	}
+	return names
+}
+
+// retain removes the packages, and the directories watched, of every package other
+// than those provided.
+func (w *watchImporter) retain(names map[string]bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	removed := make(map[string]bool)
+	for dir, d := range w.dirs {
+		if !names[d.name] {
+			removed[dir] = true
+			delete(w.dirs, dir)
+		}
+	}
+
+	for k, pkg := range w.packages {
+		if removed[pkg.Dir] {
+			delete(w.packages, k)
+		}
+	}
+}
+
+// dirStamp returns a description of the name, size and modification time of each
+// file in the directory, which changes whenever the files do. Subdirectories are
+// other packages, so they aren't included.
+func dirStamp(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%v %v %v\n", filepath.Join(dir, e.Name()), info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
+
+// symmetricDifference returns the names only present in one of the slices provided.
+func symmetricDifference(a, b []string) []string {
+	count := make(map[string]int)
+	for _, name := range a {
+		count[name]++
+	}
+	for _, name := range b {
+		count[name]--
+	}
+
+	var diff []string
+	for name, c := range count {
+		if c != 0 {
+			diff = append(diff, name)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,177 @@
+package depth
+
+import (
+	"go/build"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// watchContext returns a build.Context that resolves packages within the module in
+// the directory provided.
+func watchContext(dir string) *build.Context {
+	ctx := build.Default
+	ctx.Dir = dir
+	return &ctx
+}
+
+func TestWatcher_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/w\n",
+		"a/a.go": "package a\n\nimport _ \"example.com/w/b\"\n",
+		"b/b.go": "package b\n\nimport _ \"strings\"\n",
+		"c/c.go": "package c\n",
+	})
+
+	counter := countingImporter{ctx: watchContext(dir)}
+	w := Watcher{Tree: &Tree{Dir: dir, Importer: &counter}, Patterns: []string{"./a"}}
+
+	tests := []struct {
+		change  map[string]string
+		changed []string
+		imports int
+		deps    []string
+	}{
+		{nil, nil, 3, []string{"example.com/w/b", "strings"}},
+		{nil, nil, 0, []string{"example.com/w/b", "strings"}},
+		{map[string]string{"a/a.go": "package a\n\nimport _ \"example.com/w/c\"\n"}, []string{"example.com/w/a"}, 2, []string{"example.com/w/c"}},
+		{map[string]string{"b/b.go": "package b\n\nimport _ \"fmt\"\n"}, nil, 0, []string{"example.com/w/c"}},
+		{map[string]string{"c/c.go": "package c\n\nimport _ \"example.com/w/b\"\n"}, []string{"example.com/w/c"}, 3, []string{"example.com/w/b", "example.com/w/c", "fmt"}},
+	}
+
+	for idx, tt := range tests {
+		writeFiles(t, dir, tt.change)
+		if changed := w.Changed(); !reflect.DeepEqual(changed, tt.changed) {
+			t.Fatalf("[%v] Unexpected Changed, expected=%v, got=%v", idx, tt.changed, changed)
+		}
+
+		counter.imports = 0
+		tr, err := w.Resolve()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var deps []string
+		for _, n := range tr.Graph.Nodes() {
+			if n.Name != "example.com/w/a" {
+				deps = append(deps, n.Name)
+			}
+		}
+
+		sort.Strings(deps)
+
+		if counter.imports != tt.imports {
+			t.Fatalf("[%v] Unexpected imports, expected=%v, got=%v", idx, tt.imports, counter.imports)
+		} else if !reflect.DeepEqual(deps, tt.deps) {
+			t.Fatalf("[%v] Unexpected deps, expected=%v, got=%v", idx, tt.deps, deps)
+		}
+	}
+}
+
+func TestWatcher_ChangedPatterns(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/w\n",
+		"a/a.go": "package a\n",
+	})
+
+	w := Watcher{Tree: &Tree{Dir: dir, Context: watchContext(dir)}, Patterns: []string{"./..."}}
+	if _, err := w.Resolve(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFiles(t, dir, map[string]string{"b/b.go": "package b\n"})
+	expect := []string{"example.com/w/b"}
+	if changed := w.Changed(); !reflect.DeepEqual(changed, expect) {
+		t.Fatalf("Unexpected Changed, expected=%v, got=%v", expect, changed)
+	}
+}
+
+func TestWatcher_Watch(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/w\n",
+		"a/a.go": "package a\n\nimport _ \"strings\"\n",
+	})
+
+	w := Watcher{Tree: &Tree{Dir: dir, Context: watchContext(dir)}, Patterns: []string{"./a"}, Interval: 10 * time.Millisecond}
+	events := make(chan WatchEvent)
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- w.Watch(stop, func(e WatchEvent) { events <- e })
+	}()
+
+	var first WatchEvent
+	select {
+	case first = <-events:
+	case err := <-done:
+		t.Fatalf("Unexpected Watch return, got=%v", err)
+	}
+	if first.Prev != nil || first.Err != nil || first.Tree.Graph.Node("strings") == nil {
+		t.Fatalf("Unexpected first event, got=%+v", first)
+	}
+
+	writeFiles(t, dir, map[string]string{"a/a.go": "package a\n\nimport _ \"bytes\"\n"})
+	e := <-events
+	close(stop)
+
+	if e.Prev != first.Tree || e.Err != nil {
+		t.Fatalf("Unexpected event, got=%+v", e)
+	} else if d := Diff(e.Prev, e.Tree); !reflect.DeepEqual(d.Added, []string{"bytes"}) || !reflect.DeepEqual(d.Removed, []string{"strings"}) {
+		t.Fatalf("Unexpected Diff, expected=+[bytes] -[strings], got=+%v -%v", d.Added, d.Removed)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_watchImporterMissing(t *testing.T) {
+	dir := t.TempDir()
+	w := newWatchImporter(MockImporter{
+		ImportFn: func(name, srcDir string, im build.ImportMode) (*build.Package, error) {
+			return nil, &build.NoGoError{Dir: name}
+		},
+	})
+
+	if _, err := w.Import("example.com/missing", dir, 0); err == nil {
+		t.Fatal("Expected an error for a missing package")
+	}
+	if changed := w.invalidate(); changed != nil {
+		t.Fatalf("Unexpected changes, expected=nil, got=%v", changed)
+	}
+
+	writeFiles(t, dir, map[string]string{"a.go": "package a\n"})
+	expect := []string{"example.com/missing"}
+	if changed := w.invalidate(); !reflect.DeepEqual(changed, expect) {
+		t.Fatalf("Unexpected changes, expected=%v, got=%v", expect, changed)
+	}
+}
+
+func Test_dirStamp(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.go": "package a\n"})
+
+	before := dirStamp(dir)
+	if before == "" || dirStamp(dir) != before {
+		t.Fatalf("Unexpected unstable stamp, got=%v", before)
+	}
+
+	writeFiles(t, dir, map[string]string{"sub/b.go": "package b\n"})
+	if after := dirStamp(dir); after != before {
+		t.Fatalf("Unexpected stamp change for subdirectory, expected=%v, got=%v", before, after)
+	}
+
+	writeFiles(t, dir, map[string]string{"a.go": "package a // changed\n"})
+	if after := dirStamp(dir); after == before {
+		t.Fatal("Expected stamp change for changed file")
+	}
+
+	if s := dirStamp(filepath.Join(dir, "missing")); s != "" {
+		t.Fatalf("Unexpected stamp for missing directory, got=%v", s)
+	}
+}
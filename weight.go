@@ -0,0 +1,147 @@
+package depth
+
+import (
+	"bytes"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+)
+
+// Weight measures the non-test source code of one or more packages.
+type Weight struct {
+	Files   int `json:"files"`
+	Lines   int `json:"lines"`
+	Symbols int `json:"symbols"`
+}
+
+// add returns the sum of both Weights.
+func (w Weight) add(o Weight) Weight {
+	return Weight{
+		Files:   w.Files + o.Files,
+		Lines:   w.Lines + o.Lines,
+		Symbols: w.Symbols + o.Symbols,
+	}
+}
+
+// Weight returns the Weight of the package provided, counting its Go files, their
+// lines and the top-level symbols they declare. Packages without source files, such
+// as those read by ReadTree, have no Weight.
+func (g *Graph) Weight(name string) Weight {
+	g.weightMu.Lock()
+	defer g.weightMu.Unlock()
+
+	return g.weight(name)
+}
+
+// weight returns the Weight of the package provided, measuring it the first time it
+// is requested. The weightMu must be held.
+func (g *Graph) weight(name string) Weight {
+	if w, ok := g.weights[name]; ok {
+		return w
+	}
+
+	var w Weight
+	if n := g.nodes[name]; n != nil && n.Pkg.Raw != nil {
+		w = measure(n.Pkg.Raw)
+	}
+
+	if g.weights == nil {
+		g.weights = make(map[string]Weight)
+	}
+	g.weights[name] = w
+	return w
+}
+
+// TransitiveWeight returns the combined Weight of the package provided and every
+// package it imports, directly or indirectly, other than through test imports. This
+// estimates how much source code the package pulls in, measured in files, lines and
+// symbols rather than the size of a compiled binary.
+//
+// Only the dependencies resolved by the Tree are included, so the dependencies of
+// internal packages are excluded unless ResolveInternal is set.
+func (g *Graph) TransitiveWeight(name string) Weight {
+	g.weightMu.Lock()
+	defer g.weightMu.Unlock()
+
+	if w, ok := g.transitive[name]; ok {
+		return w
+	}
+
+	var w Weight
+	seen := map[string]struct{}{name: {}}
+	queue := []string{name}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		w = w.add(g.weight(n))
+
+		for _, e := range g.imports[n] {
+			if _, ok := seen[e.To]; ok || e.Test {
+				continue
+			}
+			seen[e.To] = struct{}{}
+			queue = append(queue, e.To)
+		}
+	}
+
+	if g.transitive == nil {
+		g.transitive = make(map[string]Weight)
+	}
+	g.transitive[name] = w
+	return w
+}
+
+// measure counts the Go files of the package, their lines and the top-level symbols
+// they declare. Files that cannot be read are skipped.
+func measure(pkg *build.Package) Weight {
+	var w Weight
+	fset := token.NewFileSet()
+	for _, name := range append(append([]string{}, pkg.GoFiles...), pkg.CgoFiles...) {
+		src, err := os.ReadFile(filepath.Join(pkg.Dir, name))
+		if err != nil {
+			continue
+		}
+
+		w.Files++
+		w.Lines += bytes.Count(src, []byte("\n"))
+		if len(src) > 0 && src[len(src)-1] != '\n' {
+			w.Lines++
+		}
+
+		f, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		w.Symbols += countSymbols(f)
+	}
+	return w
+}
+
+// countSymbols returns the number of top-level functions, methods, types, variables
+// and constants declared in the file.
+func countSymbols(f *ast.File) int {
+	var count int
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			count++
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					count++
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if n.Name != "_" {
+							count++
+						}
+					}
+				}
+			}
+		}
+	}
+	return count
+}
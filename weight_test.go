@@ -0,0 +1,79 @@
+package depth
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestGraph_Weight(t *testing.T) {
+	const prefix = "github.com/KyleBanks/depth/cmd/depth/testdata/rdeps/"
+
+	var tr Tree
+	if err := tr.Resolve(prefix + "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		weight     Weight
+		transitive Weight
+	}{
+		{prefix + "a", Weight{Files: 1, Lines: 8, Symbols: 0}, Weight{Files: 3, Lines: 22, Symbols: 2}},
+		{prefix + "b", Weight{Files: 1, Lines: 9, Symbols: 1}, Weight{Files: 2, Lines: 14, Symbols: 2}},
+		{prefix + "c", Weight{Files: 1, Lines: 5, Symbols: 1}, Weight{Files: 1, Lines: 5, Symbols: 1}},
+		{"notreal", Weight{}, Weight{}},
+	}
+
+	for _, tt := range tests {
+		if w := tr.Graph.Weight(tt.name); w != tt.weight {
+			t.Fatalf("[%v] Unexpected Weight, expected=%+v, got=%+v", tt.name, tt.weight, w)
+		} else if w := tr.Graph.TransitiveWeight(tt.name); w != tt.transitive {
+			t.Fatalf("[%v] Unexpected TransitiveWeight, expected=%+v, got=%+v", tt.name, tt.transitive, w)
+		} else if w, ok := tr.Graph.transitive[tt.name]; !ok || w != tt.transitive {
+			t.Fatalf("[%v] Expected TransitiveWeight to be cached, got=%+v", tt.name, w)
+		}
+	}
+}
+
+func TestGraph_TransitiveWeightTest(t *testing.T) {
+	tr := Tree{ResolveTest: true}
+	if err := tr.Resolve("github.com/KyleBanks/depth/cmd/depth/testdata/cycles/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Test imports aren't part of the binary.
+	name := tr.Root.Name
+	if w, tw := tr.Graph.Weight(name), tr.Graph.TransitiveWeight(name); w != tw {
+		t.Fatalf("Unexpected TransitiveWeight, expected=%+v, got=%+v", w, tw)
+	}
+}
+
+func Test_countSymbols(t *testing.T) {
+	src := `package foo
+
+import "strings"
+
+const (
+	A = iota
+	B
+)
+
+var _, c = 1, 2
+
+type T struct{}
+
+func (T) Method() {}
+
+func F() string { return strings.ToLower("F") }
+`
+
+	f, err := parser.ParseFile(token.NewFileSet(), "foo.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count := countSymbols(f); count != 6 {
+		t.Fatalf("Unexpected count, expected=%v, got=%v", 6, count)
+	}
+}